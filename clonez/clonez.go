@@ -0,0 +1,182 @@
+// Package clonez is deep copy of nested slices, maps, pointers and structs.
+package clonez
+
+import (
+	"encoding/json"
+	"reflect"
+	"unsafe"
+)
+
+// Cloner is implemented by types that copy themselves, Deep call Clone instead of reflection when result is assignable to the type.
+type Cloner interface {
+	Clone() any
+}
+
+var (
+	clonerType   = reflect.TypeOf((*Cloner)(nil)).Elem()
+	jsonMapType  = reflect.TypeOf(map[string]any(nil))
+	jsonListType = reflect.TypeOf([]any(nil))
+)
+
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int //Slices of the same array with different length are different
+}
+
+type cloner struct {
+	visited map[visitKey]reflect.Value
+	fast    map[visitKey]any //Visited of fastJson
+}
+
+// Deep return deep copy of v: slices, arrays, maps, pointers, interfaces and exported struct fields are copied recursively,
+// shared or cyclic references stay shared or cyclic in the copy. Types implementing Cloner are copied by Clone.
+//
+// Unexported struct fields, channels and functions are copied by assignment as they can't be set by reflection.
+// map[string]any and []any from json are copied without reflection, also keeping shared and cyclic references.
+func Deep[T any](v T) T {
+	c := &cloner{fast: make(map[visitKey]any)}
+	if out, ok := c.fastJson(any(v)); ok {
+		if out == nil {
+			return v
+		}
+		return out.(T)
+	}
+	c.visited = make(map[visitKey]reflect.Value)
+	var out T
+	if cv := c.deep(reflect.ValueOf(&v).Elem()); cv.IsValid() {
+		reflect.ValueOf(&out).Elem().Set(cv)
+	}
+	return out
+}
+
+// fastJson copy json-like value keeping shared and cyclic maps and slices, not ok if there is other type
+func (c *cloner) fastJson(v any) (any, bool) {
+	switch vv := v.(type) {
+	case nil, string, float64, bool, json.Number:
+		return v, true
+	case map[string]any:
+		if vv == nil {
+			return vv, true
+		}
+		key := visitKey{ptr: reflect.ValueOf(vv).Pointer(), typ: jsonMapType}
+		if out, ok := c.fast[key]; ok {
+			return out, true
+		}
+		out := make(map[string]any, len(vv))
+		c.fast[key] = out
+		for k, v1 := range vv {
+			cv, ok := c.fastJson(v1)
+			if !ok {
+				return nil, false
+			}
+			out[k] = cv
+		}
+		return out, true
+	case []any:
+		if vv == nil {
+			return vv, true
+		}
+		key := visitKey{ptr: uintptr(unsafe.Pointer(unsafe.SliceData(vv))), typ: jsonListType, len: len(vv)}
+		if out, ok := c.fast[key]; ok {
+			return out, true
+		}
+		out := make([]any, len(vv), cap(vv))
+		c.fast[key] = out
+		for i, v1 := range vv {
+			cv, ok := c.fastJson(v1)
+			if !ok {
+				return nil, false
+			}
+			out[i] = cv
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func (c *cloner) deep(v reflect.Value) reflect.Value {
+	if out, ok := c.hook(v); ok {
+		return out
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+		if out, ok := c.visited[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		c.visited[key] = out
+		out.Elem().Set(c.deep(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(c.deep(v.Elem()))
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+		if out, ok := c.visited[key]; ok {
+			return out
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.visited[key] = out
+		for iter := v.MapRange(); iter.Next(); {
+			out.SetMapIndex(iter.Key(), c.deep(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		key := visitKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len()}
+		if out, ok := c.visited[key]; ok {
+			return out
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		c.visited[key] = out
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.deep(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.deep(v.Index(i)))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v) //Unexported fields by assignment
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out.Field(i).Set(c.deep(v.Field(i)))
+			}
+		}
+		return out
+	}
+	return v
+}
+
+// hook call Cloner of v if any
+func (c *cloner) hook(v reflect.Value) (reflect.Value, bool) {
+	if !v.IsValid() || !v.CanInterface() || !v.Type().Implements(clonerType) ||
+		(v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return reflect.Value{}, false
+	}
+	res := reflect.ValueOf(v.Interface().(Cloner).Clone())
+	if !res.IsValid() || !res.Type().AssignableTo(v.Type()) {
+		return reflect.Value{}, false
+	}
+	out := reflect.New(v.Type()).Elem()
+	out.Set(res)
+	return out, true
+}
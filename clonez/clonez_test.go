@@ -0,0 +1,150 @@
+package clonez
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type node struct {
+	Name     string
+	Next     *node
+	Children []*node
+	Meta     map[string]any
+	Tags     [2][]string
+	hidden   []int
+}
+
+type counter struct {
+	N *int
+}
+
+func (c counter) Clone() any {
+	n := *c.N + 100
+	return counter{N: &n}
+}
+
+type badCloner struct {
+	N []int
+}
+
+func (b badCloner) Clone() any {
+	return "not assignable"
+}
+
+func TestDeep(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{name: "nil", v: nil},
+		{name: "int", v: 1},
+		{name: "json tree", v: map[string]any{"a": []any{1.0, "x", map[string]any{"b": true}}, "n": json.Number("1"), "z": nil}},
+		{name: "typed nested", v: []map[string][]int{{"a": {1, 2}}, nil}},
+		{name: "mixed tree", v: map[string]any{"a": []int{1}, "b": []any{map[string]int{"c": 1}}}},
+		{name: "struct", v: node{Name: "a", Meta: map[string]any{"k": []string{"v"}}, Tags: [2][]string{{"x"}, nil}}},
+		{name: "time", v: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Deep(tt.v); !reflect.DeepEqual(got, tt.v) {
+				t.Errorf("Deep() = %v, want %v", got, tt.v)
+			}
+		})
+	}
+}
+
+func TestDeepIndependent(t *testing.T) {
+	t.Parallel()
+	in := []map[string]any{{"a": []any{1.0, map[string]any{"b": 2.0}}}}
+	out := Deep(in)
+	out[0]["a"].([]any)[1].(map[string]any)["b"] = 3.0
+	out[0]["c"] = 4.0
+	if want := []map[string]any{{"a": []any{1.0, map[string]any{"b": 2.0}}}}; !reflect.DeepEqual(in, want) {
+		t.Errorf("Deep() share memory, in = %v", in)
+	}
+
+	n := &node{Name: "a", Meta: map[string]any{"k": []int{1}}, Tags: [2][]string{{"x"}}, hidden: []int{1}}
+	c := Deep(n)
+	c.Meta["k"].([]int)[0] = 9
+	c.Tags[0][0] = "y"
+	if n.Meta["k"].([]int)[0] != 1 || n.Tags[0][0] != "x" {
+		t.Errorf("Deep() share memory, n = %+v", n)
+	}
+	if &c.hidden[0] != &n.hidden[0] {
+		t.Errorf("Deep() unexported field should be assigned")
+	}
+}
+
+func TestDeepCycle(t *testing.T) {
+	t.Parallel()
+	a := &node{Name: "a"}
+	b := &node{Name: "b", Next: a}
+	a.Next = b
+	a.Children = []*node{a, b}
+	c := Deep(a)
+	if c == a || c.Next == b || c.Next.Next != c || c.Children[0] != c || c.Children[1] != c.Next {
+		t.Errorf("Deep() does not keep cycle or shared pointers")
+	}
+
+	m := map[string]any{"x": 1.0}
+	m["self"] = m
+	cm := Deep(m)
+	cm["x"] = 2.0
+	if self := cm["self"].(map[string]any); self["x"] != 2.0 || m["x"] != 1.0 {
+		t.Errorf("Deep() cyclic map = %v", self)
+	}
+}
+
+func TestDeepCloner(t *testing.T) {
+	t.Parallel()
+	n := 1
+	in := map[string]counter{"a": {N: &n}}
+	out := Deep(in)
+	if *out["a"].N != 101 {
+		t.Errorf("Deep() Cloner = %v, want 101", *out["a"].N)
+	}
+	bad := badCloner{N: []int{1}}
+	if got := Deep(bad); &got.N[0] == &bad.N[0] || !reflect.DeepEqual(got, bad) {
+		t.Errorf("Deep() should fallback to reflection for not assignable Clone result")
+	}
+}
+
+func BenchmarkDeepJson(b *testing.B) {
+	v := map[string]any{"a": []any{1.0, "x", map[string]any{"b": true, "c": []any{1.0, 2.0, 3.0}}}}
+	for i := 0; i < b.N; i++ {
+		_ = Deep(v)
+	}
+}
+
+func TestDeepShared(t *testing.T) {
+	t.Parallel()
+	shared := map[string]any{"x": 1.0}
+	list := []any{"a"}
+	in := map[string]any{"a": shared, "b": []any{shared, list}, "c": list}
+	out := Deep(in)
+	out["a"].(map[string]any)["x"] = 2.0
+	if got := out["b"].([]any)[0].(map[string]any)["x"]; got != 2.0 || shared["x"] != 1.0 {
+		t.Errorf("Deep() shared map is not shared, got %v", got)
+	}
+	out["c"].([]any)[0] = "b"
+	if got := out["b"].([]any)[1].([]any)[0]; got != "b" || list[0] != "a" {
+		t.Errorf("Deep() shared slice is not shared, got %v", got)
+	}
+
+	//DAG with 2 references per level is 2^n paths, must be linear
+	node := map[string]any{"leaf": true}
+	for i := 0; i < 64; i++ {
+		node = map[string]any{"l": node, "r": node}
+	}
+	start := time.Now()
+	c := Deep(node)
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Deep() DAG took %v", d)
+	}
+	if reflect.ValueOf(c["l"]).Pointer() != reflect.ValueOf(c["r"]).Pointer() {
+		t.Errorf("Deep() DAG is not shared")
+	}
+}
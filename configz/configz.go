@@ -0,0 +1,336 @@
+package configz
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zev-zakaryan/go-util/conv"
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+const Sep = "." //Path separator of Get, Origin and changed keys, the same as conv.GetItems
+
+// Source is one configuration layer.
+type Source interface {
+	Name() string
+	Load() (map[string]any, error)
+}
+
+// Watcher is Source that can tell it changed since last Load e.g. File, used by Config.Watch.
+type Watcher interface {
+	Changed() bool
+}
+
+// Config is layered configuration, later source has higher precedence. Safe for concurrent use.
+//
+// Layers are merged as mapz.ApplyMergePatch: nested maps are merged, other values replace, null removes the key.
+type Config struct {
+	mu       sync.RWMutex
+	sources  []Source
+	data     map[string]any
+	origin   map[string]string
+	onChange []func(changed []string)
+}
+
+// New create Config and load all sources, sources are ordered from lowest to highest precedence.
+func New(sources ...Source) (*Config, error) {
+	c := &Config{sources: sources}
+	if err := c.Load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Load (re)load all sources. On error, current data is kept. OnChange callbacks are called if any value changed.
+func (c *Config) Load() error {
+	data := map[string]any{}
+	flats := make([]map[string]any, len(c.sources))
+	for i, s := range c.sources {
+		m, err := s.Load()
+		if err != nil {
+			return fmt.Errorf("fail load config source %v: %w", s.Name(), err)
+		}
+		data = mapz.ApplyMergePatch(data, m)
+		flats[i] = mapz.Flatten(m, Sep)
+	}
+	flat := mapz.Flatten(data, Sep)
+	origin := make(map[string]string, len(flat))
+	for k := range flat {
+		for i := len(flats) - 1; i >= 0; i-- {
+			if _, ok := flats[i][k]; ok {
+				origin[k] = c.sources[i].Name()
+				break
+			}
+		}
+	}
+
+	c.mu.Lock()
+	changed := changedKeys(mapz.Flatten(c.data, Sep), flat)
+	first := c.data == nil
+	c.data, c.origin = data, origin
+	callbacks := append([]func([]string){}, c.onChange...)
+	c.mu.Unlock()
+
+	if !first && len(changed) > 0 {
+		for _, f := range callbacks {
+			f(changed)
+		}
+	}
+	return nil
+}
+
+func changedKeys(old, cur map[string]any) []string {
+	out := []string{}
+	for k, v := range cur {
+		if ov, ok := old[k]; !ok || !reflect.DeepEqual(ov, v) {
+			out = append(out, k)
+		}
+	}
+	for k := range old {
+		if _, ok := cur[k]; !ok {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// OnChange register f to be called after Load with sorted flattened keys that are added, removed or modified.
+func (c *Config) OnChange(f func(changed []string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onChange = append(c.onChange, f)
+}
+
+// Watch poll Watcher sources every interval and Load when any changed. Call stop to end, onError is optional.
+func (c *Config) Watch(interval time.Duration, onError func(err error)) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !c.sourcesChanged() {
+					continue
+				}
+				if err := c.Load(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func (c *Config) sourcesChanged() bool {
+	for _, s := range c.sources {
+		if w, ok := s.(Watcher); ok && w.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Map return copy of merged data, can be used with conv.GetItem/GetItems.
+func (c *Config) Map() map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return mapz.ApplyMergePatch(c.data, nil) //Empty patch is deep copy
+}
+
+// Lookup return value at dotted path e.g. db.port, false if not exists.
+func (c *Config) Lookup(path string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := conv.GetItems(c.data, path, conv.OptOmitNoValue)
+	if len(out) == 0 {
+		return nil, false
+	}
+	return out[0], true
+}
+
+// Origin return name of source that the value at dotted path came from, "" if path is not a value.
+func (c *Config) Origin(path string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.origin[path]
+}
+
+// Get return value at dotted path converted by conv.To.
+func Get[T any](c *Config, path string) (T, error) {
+	v, ok := c.Lookup(path)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("config %v not found", path)
+	}
+	return conv.To[T](v)
+}
+
+// GetOr is Get with def for not found or fail conversion.
+func GetOr[T any](c *Config, path string, def T) T {
+	if v, err := Get[T](c, path); err == nil {
+		return v
+	}
+	return def
+}
+
+type mapSource struct {
+	name string
+	m    map[string]any
+}
+
+// Map is static source e.g. defaults.
+func Map(name string, m map[string]any) Source {
+	return &mapSource{name: name, m: m}
+}
+
+func (s *mapSource) Name() string {
+	return s.name
+}
+
+func (s *mapSource) Load() (map[string]any, error) {
+	return s.m, nil
+}
+
+type funcSource struct {
+	name string
+	f    func() (map[string]any, error)
+}
+
+// Func is source loaded by f every Load.
+func Func(name string, f func() (map[string]any, error)) Source {
+	return &funcSource{name: name, f: f}
+}
+
+func (s *funcSource) Name() string {
+	return s.name
+}
+
+func (s *funcSource) Load() (map[string]any, error) {
+	return s.f()
+}
+
+// FileSource is file decoded by mapz.Decode, format by extension or content. Empty file is error.
+type FileSource struct {
+	Path     string
+	Format   mapz.Format
+	Optional bool //Missing file is empty instead of error
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// File is source of file at path, format by extension or content (mapz.FormatFromPath/DetectFormat).
+func File(path string) *FileSource {
+	return &FileSource{Path: path, Format: mapz.FormatFromPath(path)}
+}
+
+// OptionalFile is File that allow the file to not exist.
+func OptionalFile(path string) *FileSource {
+	f := File(path)
+	f.Optional = true
+	return f
+}
+
+func (s *FileSource) Name() string {
+	return "file:" + s.Path
+}
+
+func (s *FileSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modTime, s.size, s.exists = s.stat()
+	bs, err := os.ReadFile(s.Path)
+	if err != nil {
+		if s.Optional && os.IsNotExist(err) {
+			return map[string]any{}, nil
+		}
+		return nil, err
+	}
+	if len(bytes.TrimSpace(bs)) == 0 { //Likely truncated while being rewritten, error keep the current data on reload
+		return nil, fmt.Errorf("file %v is empty", s.Path)
+	}
+	return mapz.Decode(bs, s.Format)
+}
+
+func (s *FileSource) stat() (time.Time, int64, bool) {
+	fi, err := os.Stat(s.Path)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return fi.ModTime(), fi.Size(), true
+}
+
+// Changed implement Watcher by comparing file modification time and size with last Load.
+func (s *FileSource) Changed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	modTime, size, exists := s.stat()
+	return exists != s.exists || size != s.size || !modTime.Equal(s.modTime)
+}
+
+type envSource struct {
+	prefix  string
+	environ func() []string
+}
+
+// Env is source of environment variables with prefix. Prefix is removed, key is lowercased and "__" is nested level.
+//
+// E.g. with prefix APP_, APP_DB__PORT=5432 is db.port, APP_HOSTS__0=a is hosts as list. Values are string.
+func Env(prefix string) Source {
+	return &envSource{prefix: prefix, environ: os.Environ}
+}
+
+func (s *envSource) Name() string {
+	return "env:" + s.prefix
+}
+
+func (s *envSource) Load() (map[string]any, error) {
+	flat := map[string]any{}
+	for _, kv := range s.environ() {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(k, s.prefix) || k == s.prefix {
+			continue
+		}
+		flat[strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(k, s.prefix), "__", Sep))] = v
+	}
+	return mapz.Unflatten(flat, Sep)
+}
+
+type flagSource struct {
+	fs *flag.FlagSet
+}
+
+// Flags is source of flags that are set in fs, flag name is dotted path e.g. -db.port=5432. fs must be parsed before Load.
+func Flags(fs *flag.FlagSet) Source {
+	return &flagSource{fs: fs}
+}
+
+func (s *flagSource) Name() string {
+	return "flags:" + s.fs.Name()
+}
+
+func (s *flagSource) Load() (map[string]any, error) {
+	flat := map[string]any{}
+	s.fs.Visit(func(f *flag.Flag) {
+		if g, ok := f.Value.(flag.Getter); ok {
+			flat[f.Name] = g.Get()
+		} else {
+			flat[f.Name] = f.Value.String()
+		}
+	})
+	return mapz.Unflatten(flat, Sep)
+}
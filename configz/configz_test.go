@@ -0,0 +1,231 @@
+package configz
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+func testEnv(prefix string, environ ...string) Source {
+	return &envSource{prefix: prefix, environ: func() []string { return environ }}
+}
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(yamlPath, []byte("db:\n  host: db.local\n  port: 5433\nlog:\n  level: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.Int("db.port", 0, "")
+	fs.String("log.level", "", "")
+	if err := fs.Parse([]string{"-db.port=6000"}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(
+		Map("defaults", map[string]any{"db": map[string]any{"host": "localhost", "port": 5432, "pool": 10}, "name": "app"}),
+		File(yamlPath),
+		OptionalFile(filepath.Join(dir, "missing.toml")),
+		testEnv("APP_", "APP_DB__HOST=db.env", "APP_HOSTS__0=a", "APP_HOSTS__1=b", "OTHER=x", "APP_=x"),
+		Flags(fs),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	tests := []struct {
+		path       string
+		want       any
+		wantOrigin string
+	}{
+		{path: "db.host", want: "db.env", wantOrigin: "env:APP_"},
+		{path: "db.port", want: 6000, wantOrigin: "flags:app"},
+		{path: "db.pool", want: 10, wantOrigin: "defaults"},
+		{path: "log.level", want: "info", wantOrigin: "file:" + yamlPath},
+		{path: "hosts.1", want: "b", wantOrigin: "env:APP_"},
+		{path: "name", want: "app", wantOrigin: "defaults"},
+	}
+	for _, tt := range tests {
+		if got, ok := c.Lookup(tt.path); !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lookup(%v) = %v, %v, want %v", tt.path, got, ok, tt.want)
+		}
+		if got := c.Origin(tt.path); got != tt.wantOrigin {
+			t.Errorf("Origin(%v) = %v, want %v", tt.path, got, tt.wantOrigin)
+		}
+	}
+	if got := c.Origin("db"); got != "" {
+		t.Errorf("Origin() of table = %v, want empty", got)
+	}
+	if port, err := Get[int](c, "db.port"); err != nil || port != 6000 {
+		t.Errorf("Get() = %v, %v", port, err)
+	}
+	if _, err := Get[int](c, "db.nope"); err == nil {
+		t.Errorf("Get() expect error for missing")
+	}
+	if _, err := Get[int](c, "db.host"); err == nil {
+		t.Errorf("Get() expect error for conversion")
+	}
+	if got := GetOr(c, "db.nope", "def"); got != "def" {
+		t.Errorf("GetOr() = %v, want def", got)
+	}
+	if got := GetOr(c, "log.level", "def"); got != "info" {
+		t.Errorf("GetOr() = %v, want info", got)
+	}
+	m := c.Map()
+	if got, err := conv.GetItem[string](m, "db.host", "."); err != nil || got != "db.env" {
+		t.Errorf("conv.GetItem() = %v, %v", got, err)
+	}
+	m["name"] = "changed"
+	if got, _ := c.Lookup("name"); got != "app" {
+		t.Errorf("Map() is not a copy")
+	}
+}
+
+func TestConfigNull(t *testing.T) {
+	t.Parallel()
+	c, err := New(
+		Map("defaults", map[string]any{"a": map[string]any{"b": 1}, "c": 1}),
+		Map("override", map[string]any{"a": nil}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if v, ok := c.Lookup("a.b"); ok {
+		t.Errorf("Lookup() removed key = %v", v)
+	}
+	if got, want := c.Map(), map[string]any{"c": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+}
+
+func TestConfigLoadError(t *testing.T) {
+	t.Parallel()
+	if _, err := New(File(filepath.Join(t.TempDir(), "missing.json"))); err == nil {
+		t.Errorf("New() expect error for missing file")
+	}
+	path := filepath.Join(t.TempDir(), "app.json")
+	if err := os.WriteFile(path, []byte(`{"a":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fc, err := New(File(path))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	changed := 0
+	fc.OnChange(func([]string) { changed++ })
+	if err := os.WriteFile(path, []byte(" \n"), 0o600); err != nil { //Truncated by editor before rewrite
+		t.Fatal(err)
+	}
+	if err := fc.Load(); err == nil {
+		t.Errorf("Load() expect error for empty file")
+	}
+	if got, _ := fc.Lookup("a"); got != 1.0 || changed != 0 {
+		t.Errorf("Load() empty file must keep data, got %v, changed %v", got, changed)
+	}
+	fail := false
+	c, err := New(Func("func", func() (map[string]any, error) {
+		if fail {
+			return nil, errors.New("fail")
+		}
+		return map[string]any{"a": 1}, nil
+	}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	fail = true
+	if err := c.Load(); err == nil {
+		t.Errorf("Load() expect error")
+	}
+	if got, _ := c.Lookup("a"); got != 1 {
+		t.Errorf("Load() error must keep data, got %v", got)
+	}
+}
+
+func TestConfigOnChange(t *testing.T) {
+	t.Parallel()
+	n := 0
+	c, err := New(Func("func", func() (map[string]any, error) {
+		n++
+		if n == 1 {
+			return map[string]any{"a": 1, "b": map[string]any{"c": 2}, "d": 3}, nil
+		}
+		return map[string]any{"a": 1, "b": map[string]any{"c": 20}, "e": 4}, nil
+	}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var got []string
+	c.OnChange(func(changed []string) {
+		got = changed
+	})
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"b.c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OnChange() = %v, want %v", got, want)
+	}
+	got = nil
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("OnChange() called without change: %v", got)
+	}
+}
+
+func TestConfigWatch(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "app.json")
+	if err := os.WriteFile(path, []byte(`{"a":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(File(path))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	changedCh := make(chan []string, 1)
+	c.OnChange(func(changed []string) {
+		changedCh <- changed
+	})
+	var mu sync.Mutex
+	var errs []error
+	stop := c.Watch(5*time.Millisecond, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+	defer stop()
+	//Replace by rename so the poller never see a truncated or partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(`{"a":2,"b":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case changed := <-changedCh:
+		if want := []string{"a", "b"}; !reflect.DeepEqual(changed, want) {
+			t.Errorf("Watch() changed = %v, want %v", changed, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watch() did not reload")
+	}
+	if got, _ := Get[int](c, "a"); got != 2 {
+		t.Errorf("Get() after reload = %v, want 2", got)
+	}
+	stop()
+	stop() //Safe to call twice
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) > 0 {
+		t.Errorf("Watch() errors = %v", errs)
+	}
+}
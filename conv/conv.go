@@ -1,3 +1,5 @@
+// Package conv is conversion between types and access to nested values by path.
+// It is the base package of go-util and imports no other go-util package, so stringz, mapz, slicez and others can build on it.
 package conv
 
 //
@@ -17,12 +19,16 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-
-	"github.com/zev-zakaryan/go-util/stringz"
 )
 
 type Option string
 
+// OrderedGetter is map-like object that GetItems traverse in its own key order instead of sorted keys e.g. mapz.Ordered
+type OrderedGetter interface {
+	OrderedKeys() []string
+	OrderedGet(key string) (any, bool)
+}
+
 const (
 	DotAlternative        = "․"
 	OptOmitNoValue Option = "omit no value" //We ignore undefined if exists such as javascript, in go completely ignore null
@@ -203,8 +209,15 @@ func getItems(obj any, keys []string, out *[]any, opts map[Option]struct{}) {
 		*out = append(*out, obj)
 		return
 	}
+	if obj == nil { //Nothing under null, same as missing key in the middle of path
+		return
+	}
 	key := keys[0]
 	keys = keys[1:]
+	if o, ok := obj.(OrderedGetter); ok {
+		getItemsOrdered(o, keys, out, opts, key)
+		return
+	}
 	//Note: If you have a pointer to a slice instead of a slice, you'll need to use Elem() to get the underlying value. e.g. reflect.TypeOf(reflect.ValueOf(t).Elem().Interface()).Kind()
 	switch reflect.TypeOf(obj).Kind() {
 	case reflect.Slice:
@@ -255,6 +268,31 @@ func getItemsMapReg(m reflect.Value, keys []string, out *[]any, opts map[Option]
 		}
 	}
 }
+func getItemsOrdered(o OrderedGetter, keys []string, out *[]any, opts map[Option]struct{}, key string) {
+	if key == "#" || key == "#v" || key == "#k" || strings.HasPrefix(key, "^") {
+		var reg *regexp.Regexp
+		if strings.HasPrefix(key, "^") {
+			var err error
+			if reg, err = regexp.Compile(strings.ReplaceAll(key, DotAlternative, ".")); err != nil {
+				return
+			}
+		}
+		for _, k := range o.OrderedKeys() { //Keep object order
+			if key == "#k" {
+				getItems(k, keys, out, opts)
+			} else if reg == nil || reg.MatchString(k) {
+				v, _ := o.OrderedGet(k)
+				getItems(v, keys, out, opts)
+			}
+		}
+		return
+	}
+	if v, ok := o.OrderedGet(key); ok {
+		getItems(v, keys, out, opts)
+	} else if len(keys) == 0 { //get null for final missing by default
+		getItems(nil, keys, out, opts)
+	}
+}
 func getItemsSlice(obj any, keys []string, out *[]any, opts map[Option]struct{}, key string) {
 	sl := reflect.ValueOf(obj)
 	if key == "#" || key == "#v" {
@@ -402,20 +440,20 @@ func toString(obj any) (v any) {
 		for i1, v1 := range objV {
 			objBs[i1] = uint16(v1)
 		}
-		v = stringz.ToJson(objBs, "")
+		v = toJson(objBs)
 	default: //any=any. []any will match only []any (also slice only, not array). The same as map[any]any.
 		rv := reflect.ValueOf(obj)
 		switch rv.Kind() {
 		case reflect.Array, reflect.Map, reflect.Slice: //Beware marshal of byte slice will be base64, we handle above
-			v = stringz.ToJson(obj, "")
+			v = toJson(obj)
 		case reflect.Func:
 			if v = getFuncBodyString(getFuncAST(getFuncInfo(obj))); v == "" {
 				v = fmt.Sprintf("%T", obj)
 			}
 		case reflect.Struct: //match struct{} (instance)
-			v = stringz.ToJson(obj, "")
+			v = toJson(obj)
 		default: //reflect.Interface cant be send as param
-			if v = stringz.ToJson(obj, ""); v == "" {
+			if v = toJson(obj); v == "" {
 				v = fmt.Sprintf("%+v", obj)
 			}
 		}
@@ -423,6 +461,12 @@ func toString(obj any) (v any) {
 	return
 }
 
+// toJson is the same as stringz.ToJson without indent, it is here as conv must not import stringz, see package doc
+func toJson(obj any) string {
+	bs, _ := json.Marshal(obj)
+	return string(bs)
+}
+
 func matchStructFunc(f *ast.FuncDecl, funcname string) bool {
 	for _, l := range f.Recv.List { // fn is *ast.FuncDecl
 		var fullname string
@@ -3920,6 +3920,28 @@ func TestGetItems(t *testing.T) {
 	}
 }
 
+func TestGetItemsNullInPath(t *testing.T) {
+	t.Parallel()
+	obj := map[string]any{"a": nil, "b": []any{nil, map[string]any{"c": 1}}}
+	tests := []struct {
+		name string
+		keys string
+		want []any
+	}{
+		{name: "null is final value", keys: "a", want: []any{nil}},
+		{name: "nothing under null", keys: "a.x", want: []any{}},
+		{name: "nothing under null in list", keys: "b.#.c", want: []any{1}},
+		{name: "nothing under null by index", keys: "b.0.c", want: []any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetItems(obj, tt.keys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetItemsWithOptOmitNoValue(t *testing.T) {
 	var va any
 	var vc64 complex64
@@ -4203,6 +4225,78 @@ func TestGetItemsWithOptOmitNoValue(t *testing.T) {
 	}
 }
 
+type orderedTest struct {
+	keys []string
+	m    map[string]any
+}
+
+func (o orderedTest) OrderedKeys() []string {
+	return o.keys
+}
+
+func (o orderedTest) OrderedGet(key string) (any, bool) {
+	v, ok := o.m[key]
+	return v, ok
+}
+
+func TestGetItemsOrderedGetter(t *testing.T) {
+	t.Parallel()
+	obj := map[string]any{
+		"path1": orderedTest{
+			keys: []string{"z", "a", "value_2", "value_1"},
+			m:    map[string]any{"z": 1, "a": []any{orderedTest{keys: []string{"y", "x"}, m: map[string]any{"y": "Y", "x": "X"}}}, "value_2": 2, "value_1": nil},
+		},
+	}
+	type args struct {
+		obj  any
+		keys string
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want []any
+	}{
+		{
+			name: "#k keep order",
+			args: args{obj: obj, keys: "path1.#k"},
+			want: []any{"z", "a", "value_2", "value_1"},
+		},
+		{
+			name: "# keep order",
+			args: args{obj: obj, keys: "path1.a.0.#"},
+			want: []any{"Y", "X"},
+		},
+		{
+			name: "regex keep order",
+			args: args{obj: obj, keys: "path1.^value_"},
+			want: []any{2, nil},
+		},
+		{
+			name: "regex omit no value",
+			args: args{obj: obj, keys: "path1.^value_", opts: []Option{OptOmitNoValue}},
+			want: []any{2},
+		},
+		{
+			name: "key",
+			args: args{obj: obj, keys: "path1.a.0.x"},
+			want: []any{"X"},
+		},
+		{
+			name: "missing final key",
+			args: args{obj: obj, keys: "path1.nope"},
+			want: []any{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetItems(tt.args.obj, tt.args.keys, tt.args.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestA1ColumnDecode(t *testing.T) {
 	type args struct {
 		column string
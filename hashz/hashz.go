@@ -0,0 +1,166 @@
+// Package hashz is checksums, hashes and HMAC with configurable output encoding, including stable hash of any value by canonical JSON.
+package hashz
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"io"
+	"strings"
+
+	"github.com/zev-zakaryan/go-util/stringz"
+)
+
+type Algorithm string
+
+const (
+	Crc32    Algorithm = "crc32"    //IEEE, the same as stringz.ToCrc32
+	Crc32C   Algorithm = "crc32c"   //Castagnoli
+	Crc64    Algorithm = "crc64"    //ECMA
+	Crc64Iso Algorithm = "crc64iso" //ISO
+	Fnv32a   Algorithm = "fnv32a"
+	Fnv64a   Algorithm = "fnv64a"
+	Fnv128a  Algorithm = "fnv128a"
+	Xxh64    Algorithm = "xxh64" //Fast non-cryptographic hash, stable across processes unlike hash/maphash
+	Sha224   Algorithm = "sha224"
+	Sha256   Algorithm = "sha256"
+	Sha384   Algorithm = "sha384"
+	Sha512   Algorithm = "sha512"
+)
+
+type Encoding string
+
+const (
+	HexLower  Encoding = "hex"
+	HexUpper  Encoding = "HEX"
+	Base32    Encoding = "base32"    //RFC 4648 without padding
+	Base64    Encoding = "base64"    //Standard with padding
+	Base64Url Encoding = "base64url" //URL safe without padding
+)
+
+var (
+	tableCastagnoli = crc32.MakeTable(crc32.Castagnoli)
+	tableEcma       = crc64.MakeTable(crc64.ECMA)
+	tableIso        = crc64.MakeTable(crc64.ISO)
+)
+
+var constructors = map[Algorithm]func() hash.Hash{
+	Crc32:    func() hash.Hash { return crc32.NewIEEE() },
+	Crc32C:   func() hash.Hash { return crc32.New(tableCastagnoli) },
+	Crc64:    func() hash.Hash { return crc64.New(tableEcma) },
+	Crc64Iso: func() hash.Hash { return crc64.New(tableIso) },
+	Fnv32a:   func() hash.Hash { return fnv.New32a() },
+	Fnv64a:   func() hash.Hash { return fnv.New64a() },
+	Fnv128a:  func() hash.Hash { return fnv.New128a() },
+	Xxh64:    func() hash.Hash { return newXxh64() },
+	Sha224:   sha256.New224,
+	Sha256:   sha256.New,
+	Sha384:   sha512.New384,
+	Sha512:   sha512.New,
+}
+
+// Hasher is streaming hash.Hash (io.Writer) with encoded output, create by New or NewHmac.
+type Hasher struct {
+	hash.Hash
+}
+
+// New create Hasher of alg, write data to it then get SumString.
+func New(alg Algorithm) (*Hasher, error) {
+	f, ok := constructors[alg]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %q", alg)
+	}
+	return &Hasher{Hash: f()}, nil
+}
+
+// NewHmac create HMAC Hasher of alg with key, should be used with SHA-2.
+func NewHmac(alg Algorithm, key []byte) (*Hasher, error) {
+	f, ok := constructors[alg]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %q", alg)
+	}
+	return &Hasher{Hash: hmac.New(f, key)}, nil
+}
+
+// SumString return encoded sum of data written so far.
+func (h *Hasher) SumString(enc Encoding) (string, error) {
+	return Encode(h.Sum(nil), enc)
+}
+
+// WriteString implement io.StringWriter.
+func (h *Hasher) WriteString(s string) (int, error) {
+	return io.WriteString(h.Hash, s)
+}
+
+// Encode sum to enc. Checksums are big-endian e.g. Crc32 of "123456789" is CBF43926 in HexUpper.
+func Encode(sum []byte, enc Encoding) (string, error) {
+	switch enc {
+	case HexLower:
+		return hex.EncodeToString(sum), nil
+	case HexUpper:
+		return strings.ToUpper(hex.EncodeToString(sum)), nil
+	case Base32:
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum), nil
+	case Base64:
+		return base64.StdEncoding.EncodeToString(sum), nil
+	case Base64Url:
+		return base64.RawURLEncoding.EncodeToString(sum), nil
+	}
+	return "", fmt.Errorf("unknown encoding %q", enc)
+}
+
+// Bytes return encoded hash of data.
+func Bytes(alg Algorithm, data []byte, enc Encoding) (string, error) {
+	return Reader(alg, bytes.NewReader(data), enc)
+}
+
+// String return encoded hash of s.
+func String(alg Algorithm, s string, enc Encoding) (string, error) {
+	return Reader(alg, strings.NewReader(s), enc)
+}
+
+// Reader return encoded hash of all data from r.
+func Reader(alg Algorithm, r io.Reader, enc Encoding) (string, error) {
+	h, err := New(alg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return h.SumString(enc)
+}
+
+// Hmac return encoded HMAC of data with key.
+func Hmac(alg Algorithm, key, data []byte, enc Encoding) (string, error) {
+	h, err := NewHmac(alg, key)
+	if err != nil {
+		return "", err
+	}
+	_, _ = h.Write(data) //hash.Hash never return error
+	return h.SumString(enc)
+}
+
+// Value return encoded hash of canonical JSON of v, so equal documents always hash equally
+// e.g. map[string]any{"a": 1, "b": 2.0}, struct{ B, A int }{2, 1} with json tags a, b and `{"b":2,"a":1.0}` as json.RawMessage.
+func Value(alg Algorithm, v any, enc Encoding) (string, error) {
+	bs, err := Canonical(v)
+	if err != nil {
+		return "", err
+	}
+	return Bytes(alg, bs, enc)
+}
+
+// Canonical is stringz.ToJsonE with JSONOptions.Canonical, RFC 8785 JSON with sorted object keys, no insignificant space and numbers in shortest form.
+func Canonical(v any) ([]byte, error) {
+	return stringz.JSONOptions{Canonical: true}.Marshal(v)
+}
@@ -0,0 +1,162 @@
+package hashz
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zev-zakaryan/go-util/stringz"
+)
+
+func TestString(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alg  Algorithm
+		s    string
+		want string
+	}{
+		{alg: Crc32, s: "123456789", want: "cbf43926"},
+		{alg: Crc32C, s: "123456789", want: "e3069283"},
+		{alg: Crc64, s: "123456789", want: "995dc9bbdf1939fa"},
+		{alg: Crc64Iso, s: "123456789", want: "b90956c775a41001"},
+		{alg: Fnv32a, s: "a", want: "e40c292c"},
+		{alg: Fnv64a, s: "a", want: "af63dc4c8601ec8c"},
+		{alg: Fnv128a, s: "a", want: "d228cb696f1a8caf78912b704e4a8964"},
+		{alg: Xxh64, s: "", want: "ef46db3751d8e999"},
+		{alg: Xxh64, s: "a", want: "d24ec4f1a98c6e5b"},
+		{alg: Xxh64, s: "abc", want: "44bc2cf5ad770999"},
+		{alg: Xxh64, s: "Nobody inspects the spammish repetition", want: "fbcea83c8a378bf1"},
+		{alg: Sha224, s: "abc", want: "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7"},
+		{alg: Sha256, s: "abc", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{alg: Sha384, s: "", want: "38b060a751ac96384cd9327eb1b1e36a21fdb71114be07434c0cc7bf63f6e1da274edebfe76f65fbd51ad2f14898b95b"},
+		{alg: Sha512, s: "", want: "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"},
+	}
+	for _, tt := range tests {
+		if got, err := String(tt.alg, tt.s, HexLower); err != nil || got != tt.want {
+			t.Errorf("String(%v, %q) = %v, %v, want %v", tt.alg, tt.s, got, err, tt.want)
+		}
+	}
+	if _, err := String("md5", "", HexLower); err == nil {
+		t.Errorf("String() expect error for unknown algorithm")
+	}
+	if got, _ := String(Crc32, "abc", HexUpper); got != stringz.ToCrc32("abc") {
+		t.Errorf("String() = %v, want the same as stringz.ToCrc32 %v", got, stringz.ToCrc32("abc"))
+	}
+}
+
+func TestXxh64Streaming(t *testing.T) {
+	t.Parallel()
+	data := []byte(strings.Repeat("0123456789abcdefghijklmnopqrstuvwxyz", 30))
+	for n := 0; n <= len(data); n += 7 {
+		want, _ := Bytes(Xxh64, data[:n], HexLower)
+		h, _ := New(Xxh64)
+		for i := 0; i < n; i += 5 { //Write in small chunks across stripes
+			end := i + 5
+			if end > n {
+				end = n
+			}
+			_, _ = h.Write(data[i:end])
+		}
+		if got, _ := h.SumString(HexLower); got != want {
+			t.Fatalf("streaming %v bytes = %v, want %v", n, got, want)
+		}
+		h.Reset()
+		_, _ = h.Write(data[:n])
+		if got, _ := h.SumString(HexLower); got != want {
+			t.Fatalf("after Reset %v bytes = %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	sum := []byte{0xfb, 0xff, 0x01}
+	tests := []struct {
+		enc  Encoding
+		want string
+	}{
+		{enc: HexLower, want: "fbff01"},
+		{enc: HexUpper, want: "FBFF01"},
+		{enc: Base32, want: "7P7QC"},
+		{enc: Base64, want: "+/8B"},
+		{enc: Base64Url, want: "-_8B"},
+	}
+	for _, tt := range tests {
+		if got, err := Encode(sum, tt.enc); err != nil || got != tt.want {
+			t.Errorf("Encode(%v) = %v, %v, want %v", tt.enc, got, err, tt.want)
+		}
+	}
+	if _, err := Encode(sum, "base58"); err == nil {
+		t.Errorf("Encode() expect error for unknown encoding")
+	}
+}
+
+func TestHmac(t *testing.T) {
+	t.Parallel()
+	//RFC 4231 test case 2
+	got, err := Hmac(Sha256, []byte("Jefe"), []byte("what do ya want for nothing?"), HexLower)
+	if want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"; err != nil || got != want {
+		t.Errorf("Hmac() = %v, %v, want %v", got, err, want)
+	}
+	h, _ := NewHmac(Sha256, []byte("Jefe"))
+	_, _ = h.WriteString("what do ya want ")
+	_, _ = h.WriteString("for nothing?")
+	if s, _ := h.SumString(HexLower); s != got {
+		t.Errorf("NewHmac() streaming = %v, want %v", s, got)
+	}
+	if _, err := Hmac("x", nil, nil, HexLower); err == nil {
+		t.Errorf("Hmac() expect error for unknown algorithm")
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+	type doc struct {
+		B float64 `json:"b"`
+		A int     `json:"a"`
+		S string  `json:"s"`
+	}
+	equal := []any{
+		map[string]any{"a": 1, "b": 2.0, "s": "<&>"},
+		doc{B: 2, A: 1, S: "<&>"},
+		json.RawMessage(`{ "s": "<&>", "b": 2.0, "a": 1e0 }`),
+	}
+	want := `{"a":1,"b":2,"s":"<&>"}`
+	wantHash, _ := Value(Sha256, equal[0], Base64Url)
+	for _, v := range equal {
+		if got, err := Canonical(v); err != nil || string(got) != want {
+			t.Errorf("Canonical(%#v) = %s, %v, want %v", v, got, err, want)
+		}
+		if got, _ := Value(Sha256, v, Base64Url); got != wantHash {
+			t.Errorf("Value(%#v) = %v, want %v", v, got, wantHash)
+		}
+	}
+	numbers := map[string]string{
+		"0": "0", "-0.0": "0", "1.50": "1.5", "1e21": "1e+21", "1e20": "100000000000000000000", "0.000001": "0.000001",
+		"1.5e-7": "1.5e-7", "-12": "-12", "9007199254740993": "9007199254740992", "123456789012345678901234": "1.2345678901234569e+23",
+	}
+	for in, want := range numbers {
+		if got, err := Canonical(json.RawMessage(`[` + in + `]`)); err != nil || !bytes.Equal(got, []byte("["+want+"]")) {
+			t.Errorf("Canonical(%v) = %s, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := Canonical(func() {}); err == nil {
+		t.Errorf("Canonical() expect error for func")
+	}
+	if _, err := Value(Sha256, func() {}, HexLower); err == nil {
+		t.Errorf("Value() expect error for func")
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	s := strings.Repeat("x", 4096)
+	for _, alg := range []Algorithm{Crc32, Crc32C, Fnv64a, Xxh64, Sha256} {
+		b.Run(string(alg), func(b *testing.B) {
+			b.SetBytes(int64(len(s)))
+			for i := 0; i < b.N; i++ {
+				_, _ = String(alg, s, HexLower)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package mapz
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+type Format string
+
+const (
+	FormatAuto  Format = ""
+	FormatJson  Format = "json"
+	FormatJson5 Format = "json5"
+	FormatYaml  Format = "yaml"
+	FormatToml  Format = "toml"
+	FormatEnv   Format = "env"
+	FormatQuery Format = "query"
+)
+
+var (
+	regTomlTable = regexp.MustCompile(`^\[\[?\s*[A-Za-z0-9_\-."' ]+\]\]?\s*(#.*)?$`)
+	regTomlKV    = regexp.MustCompile(`^[A-Za-z0-9_\-."']+\s*=\s*("|'|\[|\{|[-+0-9]|true|false|inf|nan)`)
+	regEnvLine   = regexp.MustCompile(`^(export\s+)?[A-Za-z_][A-Za-z0-9_.\-]*=`)
+	regQuery     = regexp.MustCompile(`^\??[^\s=&]+=[^\s&]*(&[^\s=&]+(=[^\s&]*)?)*$`)
+)
+
+// Decode convert json, yaml, toml, env file or url query string to the same map shape as ToMap, so conv.GetItems/Join work on any of them.
+//
+// data is string, []byte or io.Reader. FormatAuto use DetectFormat. Numbers are float64 as json, env and query values are always string.
+//
+// yaml and toml are common subset: no anchor/alias/tag/multi-document in yaml, toml datetime is kept as string. opts apply to json and json5 only.
+func Decode(data any, format Format, opts ...Option) (map[string]any, error) {
+	var bs []byte
+	switch v := data.(type) {
+	case string:
+		bs = []byte(v)
+	case []byte:
+		bs = v
+	case io.Reader:
+		var err error
+		if bs, err = io.ReadAll(v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported data type %T", data)
+	}
+	if format == FormatAuto {
+		format = DetectFormat(bs)
+	}
+	switch format {
+	case FormatJson:
+		return ToMapE(bs, opts...)
+	case FormatJson5:
+		return ToMapE(bs, append(opts, OptLenient)...)
+	case FormatYaml:
+		return decodeYaml(string(bs))
+	case FormatToml:
+		return decodeToml(string(bs))
+	case FormatEnv:
+		return decodeEnv(string(bs))
+	case FormatQuery:
+		return decodeQuery(string(bs))
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// DetectFormat guess format from content, yaml is the fallback.
+//
+// Single line of k=v joined by & (or starting with ?) is query, lines of KEY=VALUE are env. Valid toml with a [table] line
+// or with key=value lines having lowercase keys is toml e.g. a=1, env keys are usually uppercase.
+func DetectFormat(data []byte) Format {
+	s := strings.TrimSpace(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	if strings.HasPrefix(s, "{") {
+		return FormatJson
+	}
+	if !strings.ContainsAny(s, "\r\n") && regQuery.MatchString(s) && (strings.HasPrefix(s, "?") || strings.Contains(s, "&")) {
+		return FormatQuery
+	}
+	isEnv, hasLine, hasTable, hasLowerKey := true, false, false, false
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !hasLine && (regTomlTable.MatchString(line) || regTomlKV.MatchString(line) && strings.Contains(line, " = ")) {
+			return FormatToml
+		}
+		hasLine = true
+		hasTable = hasTable || regTomlTable.MatchString(line)
+		if !regEnvLine.MatchString(line) {
+			isEnv = false
+		} else if k, _, _ := strings.Cut(line, "="); k != strings.ToUpper(k) {
+			hasLowerKey = true
+		}
+	}
+	if hasTable || isEnv && hasLowerKey {
+		if _, err := decodeToml(s); err == nil {
+			return FormatToml
+		}
+	}
+	if hasLine && isEnv {
+		return FormatEnv
+	}
+	return FormatYaml
+}
+
+// FormatFromPath return format by file extension, FormatAuto if unknown.
+func FormatFromPath(path string) Format {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasSuffix(base, ".json"):
+		return FormatJson
+	case strings.HasSuffix(base, ".json5"):
+		return FormatJson5
+	case strings.HasSuffix(base, ".yaml"), strings.HasSuffix(base, ".yml"):
+		return FormatYaml
+	case strings.HasSuffix(base, ".toml"):
+		return FormatToml
+	case strings.HasSuffix(base, ".env"), base == ".env", strings.HasPrefix(base, ".env."):
+		return FormatEnv
+	}
+	return FormatAuto
+}
+
+func decodeEnv(s string) (map[string]any, error) {
+	out := make(map[string]any)
+	for i, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		k, v, ok := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("env line %d: expect KEY=VALUE", i+1)
+		}
+		v = strings.TrimSpace(v)
+		switch {
+		case strings.HasPrefix(v, `"`):
+			end := closingQuote(v, '"')
+			if end < 0 {
+				return nil, fmt.Errorf("env line %d: unterminated double quote", i+1)
+			}
+			uv, err := strconv.Unquote(v[:end+1])
+			if err != nil {
+				return nil, fmt.Errorf("env line %d: %w", i+1, err)
+			}
+			v = uv
+		case strings.HasPrefix(v, "'"):
+			end := strings.IndexByte(v[1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("env line %d: unterminated single quote", i+1)
+			}
+			v = v[1 : end+1]
+		default:
+			if idx := strings.Index(v, " #"); idx >= 0 {
+				v = strings.TrimSpace(v[:idx])
+			}
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// closingQuote return index of quote that close s[0], skip backslash escape.
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// decodeQuery support repeated key as list and bracket e.g. a[b]=1&a[c][]=2 as {"a":{"b":"1","c":["2"]}}, empty key e.g. =x or a[][b]=x is error
+func decodeQuery(s string) (map[string]any, error) {
+	q, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(s), "?"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) //Need sort in golang for stable error
+	out := make(map[string]any)
+	for _, k := range keys {
+		path := []string{k}
+		if i := strings.IndexByte(k, '['); i > 0 && strings.HasSuffix(k, "]") {
+			path = append([]string{k[:i]}, strings.Split(k[i+1:len(k)-1], "][")...)
+		}
+		isList := path[len(path)-1] == ""
+		if isList {
+			path = path[:len(path)-1]
+		}
+		empty := len(path) == 0 || strings.HasPrefix(k, "[")
+		for _, p := range path {
+			empty = empty || p == "" //Only the last [] is list
+		}
+		if empty {
+			return nil, fmt.Errorf("query key %q has empty name", k)
+		}
+		var v any
+		if vs := q[k]; len(vs) == 1 && !isList {
+			v = vs[0]
+		} else {
+			l := make([]any, len(vs))
+			for i, v1 := range vs {
+				l[i] = v1
+			}
+			v = l
+		}
+		cur := out
+		for _, p := range path[:len(path)-1] {
+			next, ok := cur[p].(map[string]any)
+			if !ok {
+				if _, exists := cur[p]; exists {
+					return nil, fmt.Errorf("query key %q conflicts with value", k)
+				}
+				next = make(map[string]any)
+				cur[p] = next
+			}
+			cur = next
+		}
+		if _, exists := cur[path[len(path)-1]]; exists {
+			return nil, fmt.Errorf("query key %q conflicts with value", k)
+		}
+		cur[path[len(path)-1]] = v
+	}
+	return out, nil
+}
+
+// parseNumber parse yaml/toml number to float64 as json, return false if s is not a number.
+func parseNumber(s string) (float64, bool) {
+	neg := strings.HasPrefix(s, "-")
+	u := strings.TrimLeft(s, "+-")
+	if len(s)-len(u) > 1 {
+		return 0, false
+	}
+	base := 0
+	switch {
+	case strings.HasPrefix(u, "0x"):
+		base = 16
+	case strings.HasPrefix(u, "0o"):
+		base = 8
+	case strings.HasPrefix(u, "0b"):
+		base = 2
+	}
+	if base != 0 {
+		i, err := strconv.ParseUint(u[2:], base, 64)
+		if err != nil {
+			return 0, false
+		}
+		f := float64(i)
+		if neg {
+			f = -f
+		}
+		return f, true
+	}
+	switch strings.TrimPrefix(strings.ToLower(u), ".") {
+	case "inf", "infinity":
+		return math.Inf(conv.Ternary(neg, -1, 1)), true
+	case "nan":
+		return math.NaN(), true
+	}
+	if u == "" || !(u[0] >= '0' && u[0] <= '9' || u[0] == '.') {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
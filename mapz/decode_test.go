@@ -0,0 +1,432 @@
+package mapz
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	want := ToMap(`{"name":"app","db":{"host":"localhost","port":5432,"replicas":["a","b"]},"debug":true,"ratio":0.5,"empty":null}`)
+	tests := []struct {
+		name   string
+		data   any
+		format Format
+	}{
+		{
+			name:   "json",
+			data:   `{"name":"app","db":{"host":"localhost","port":5432,"replicas":["a","b"]},"debug":true,"ratio":0.5,"empty":null}`,
+			format: FormatJson,
+		},
+		{
+			name: "yaml",
+			data: []byte(`
+# comment
+name: app
+db:
+  host: "localhost"   # inline comment
+  port: 5432
+  replicas:
+  - a
+  - 'b'
+debug: true
+ratio: 0.5
+empty: ~
+`),
+			format: FormatYaml,
+		},
+		{
+			name: "toml",
+			data: strings.NewReader(`
+name = "app"
+debug = true
+ratio = 0.5
+empty = "x"
+
+[db]
+host = 'localhost' # comment
+port = 5_432
+replicas = [
+  "a",
+  "b", # trailing comma
+]
+`),
+			format: FormatToml,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data, tt.format)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			want := want
+			if tt.format == FormatToml { //toml has no null
+				want = ApplyMergePatch(want, map[string]any{"empty": "x"})
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Decode() = %v, want %v", got, want)
+			}
+			if port := conv.GetItems(got, "db.port"); !reflect.DeepEqual(port, []any{5432.0}) {
+				t.Errorf("GetItems() = %v", port)
+			}
+		})
+	}
+	if _, err := Decode(1, FormatJson); err == nil {
+		t.Errorf("Decode() expect error for unsupported data")
+	}
+	if _, err := Decode("", "xml"); err == nil {
+		t.Errorf("Decode() expect error for unknown format")
+	}
+	if got, err := Decode("a=1&a=2", FormatAuto); err != nil || !reflect.DeepEqual(got, map[string]any{"a": []any{"1", "2"}}) {
+		t.Errorf("Decode() auto = %v, %v", got, err)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		data string
+		want Format
+	}{
+		{name: "json", data: " \n{\"a\":1}", want: FormatJson},
+		{name: "json with bom", data: "\xef\xbb\xbf{}", want: FormatJson},
+		{name: "toml table", data: "# x\n[server]\nport = 80", want: FormatToml},
+		{name: "toml array table", data: "[[items]]\nname = \"a\"", want: FormatToml},
+		{name: "toml key", data: "title = \"x\"\n[a]\n", want: FormatToml},
+		{name: "toml without spaces", data: "a=1\nb=\"x\"", want: FormatToml},
+		{name: "toml table later", data: "title=\"x\"\n[server]\nport=80", want: FormatToml},
+		{name: "env", data: "# x\nexport A=1\nB_C=\"x y\"\n", want: FormatEnv},
+		{name: "env uppercase valid toml", data: "A=1\nB=\"x\"", want: FormatEnv},
+		{name: "env lowercase not toml", data: "a=hello world\nb=1", want: FormatEnv},
+		{name: "query", data: "?a=1", want: FormatQuery},
+		{name: "query many", data: "a=1&b=x%20y&c", want: FormatQuery},
+		{name: "yaml", data: "a: 1\nb:\n  - x", want: FormatYaml},
+		{name: "empty is yaml", data: "", want: FormatYaml},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+	tests := map[string]Format{
+		"a/b/config.JSON": FormatJson,
+		"c.json5":         FormatJson5,
+		"c.yml":           FormatYaml,
+		"c.yaml":          FormatYaml,
+		"c.toml":          FormatToml,
+		".env":            FormatEnv,
+		"prod.env":        FormatEnv,
+		".env.local":      FormatEnv,
+		"c.txt":           FormatAuto,
+	}
+	for path, want := range tests {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%v) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestDecodeYaml(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "document marker and scalars",
+			data: "---\na: null\nb: True\nc: -1.5e2\nd: 0x1F\ne: .inf\nf: inf\ng: '1'\nh: \"x\\ty\"\ni: it''s\nj: 'it''s'\nk: http://x.y/z\n...\nignored: 1",
+			want: map[string]any{"a": nil, "b": true, "c": -150.0, "d": 31.0, "e": math.Inf(1), "f": "inf", "g": "1", "h": "x\ty", "i": "it''s", "j": "it's", "k": "http://x.y/z"},
+		},
+		{
+			name: "sequence of mappings",
+			data: "items:\n  - name: a\n    tags: [x, 'y', 1]\n  - name: b\n    meta: {k: v, n: 2}\n  -\n    name: c\n  - - 1\n    - 2\n",
+			want: map[string]any{"items": []any{
+				map[string]any{"name": "a", "tags": []any{"x", "y", 1.0}},
+				map[string]any{"name": "b", "meta": map[string]any{"k": "v", "n": 2.0}},
+				map[string]any{"name": "c"},
+				[]any{1.0, 2.0},
+			}},
+		},
+		{
+			name: "block scalars",
+			data: "lit: |\n  line1\n    indented\n\n  line3\nfold: >-\n  a\n  b\n\n  c\nkeep: |+\n  x\n\nend: 1\n",
+			want: map[string]any{"lit": "line1\n  indented\n\nline3\n", "fold": "a b\nc", "keep": "x\n\n", "end": 1.0},
+		},
+		{
+			name: "multi-line plain and empty value",
+			data: "a: this is\n  long text\nb:\nc: {}\nd: []\n\"quoted key\": 1\n",
+			want: map[string]any{"a": "this is long text", "b": nil, "c": map[string]any{}, "d": []any{}, "quoted key": 1.0},
+		},
+		{
+			name: "empty",
+			data: "# nothing\n",
+			want: map[string]any{},
+		},
+		{
+			name:    "not mapping",
+			data:    "- a\n- b",
+			wantErr: true,
+		},
+		{
+			name:    "bad indentation",
+			data:    "a:\n    b: 1\n  c: 2",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			data:    "a: 1\na: 2",
+			wantErr: true,
+		},
+		{
+			name:    "tab",
+			data:    "a:\n\tb: 1",
+			wantErr: true,
+		},
+		{
+			name:    "anchor",
+			data:    "a: &x 1",
+			wantErr: true,
+		},
+		{
+			name:    "multi-document",
+			data:    "a: 1\n---\nb: 2",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated flow",
+			data:    "a: [1, 2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data, FormatYaml)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeToml(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "values",
+			data: "a = 0xff\nb = 0o7\nc = 0b11\nd = -inf\ne = 1e3\nf = \"\\u00e9\\n\"\ng = 1979-05-27T07:32:00Z\nh = 1979-05-27 07:32:00\ni = 1979-05-27\nj = false\n\"k.l\" = 1\nm.n = 2\n",
+			want: map[string]any{"a": 255.0, "b": 7.0, "c": 3.0, "d": math.Inf(-1), "e": 1000.0, "f": "é\n", "g": "1979-05-27T07:32:00Z", "h": "1979-05-27 07:32:00", "i": "1979-05-27", "j": false, "k.l": 1.0, "m": map[string]any{"n": 2.0}},
+		},
+		{
+			name: "multi-line strings",
+			data: "a = \"\"\"\nline1\nline2\"\"\"\nb = '''\nC:\\path\\'''\nc = \"\"\"one \\\n    two\"\"\"\n",
+			want: map[string]any{"a": "line1\nline2", "b": "C:\\path\\", "c": "one two"},
+		},
+		{
+			name: "tables and array of tables",
+			data: "[server.http]\nport = 80\n\n[[fruit]]\nname = \"apple\"\n[fruit.physical]\ncolor = \"red\"\n\n[[fruit]]\nname = \"banana\"\n[fruit.physical]\ncolor = \"yellow\"\n",
+			want: map[string]any{
+				"server": map[string]any{"http": map[string]any{"port": 80.0}},
+				"fruit": []any{
+					map[string]any{"name": "apple", "physical": map[string]any{"color": "red"}},
+					map[string]any{"name": "banana", "physical": map[string]any{"color": "yellow"}},
+				},
+			},
+		},
+		{
+			name: "inline table and nested array",
+			data: "point = { x = 1, y.z = 2 }\nempty = {}\nnested = [[1, 2], [\"a\"]]\n",
+			want: map[string]any{"point": map[string]any{"x": 1.0, "y": map[string]any{"z": 2.0}}, "empty": map[string]any{}, "nested": []any{[]any{1.0, 2.0}, []any{"a"}}},
+		},
+		{
+			name:    "duplicate key",
+			data:    "a = 1\na = 2",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate table",
+			data:    "[a]\n[a]",
+			wantErr: true,
+		},
+		{
+			name:    "value as table",
+			data:    "a = 1\n[a]",
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			data:    "a = ",
+			wantErr: true,
+		},
+		{
+			name:    "trailing garbage",
+			data:    "a = 1 b",
+			wantErr: true,
+		},
+		{
+			name:    "invalid number",
+			data:    "a = 1__0",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			data:    "a = \"x\nb = 1",
+			wantErr: true,
+		},
+		{
+			name:    "leading zero",
+			data:    "a = 01",
+			wantErr: true,
+		},
+		{
+			name:    "leading zero float",
+			data:    "a = -00.5",
+			wantErr: true,
+		},
+		{
+			name:    "inline table extended by table",
+			data:    "a = { x = 1 }\n[a]\ny = 2",
+			wantErr: true,
+		},
+		{
+			name:    "inline table extended by sub table",
+			data:    "a = { x = { y = 1 } }\n[a.x]\nz = 2",
+			wantErr: true,
+		},
+		{
+			name:    "inline table extended by dotted key",
+			data:    "a = { x = 1 }\na.y = 2",
+			wantErr: true,
+		},
+		{
+			name:    "inline table extended in inline table",
+			data:    "a = { b = { x = 1 }, b.y = 2 }",
+			wantErr: true,
+		},
+		{
+			name:    "static array extended by array table",
+			data:    "a = []\n[[a]]",
+			wantErr: true,
+		},
+		{
+			name: "zero, prefixed and dotted keys",
+			data: "a = 0\nb = 0.5\nc = 0x0F\nd = -0\ne.f = 1\ne.g = 2\n[e.h]\ni = 3",
+			want: map[string]any{"a": 0.0, "b": 0.5, "c": 15.0, "d": 0.0, "e": map[string]any{"f": 1.0, "g": 2.0, "h": map[string]any{"i": 3.0}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data, FormatToml)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvQuery(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		format  Format
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:   "env",
+			data:   "# comment\nexport A=1\nB = x y # comment\nC=\"line\\nnext\" # c\nD='raw $x'\nE=\n",
+			format: FormatEnv,
+			want:   map[string]any{"A": "1", "B": "x y", "C": "line\nnext", "D": "raw $x", "E": ""},
+		},
+		{
+			name:    "env invalid",
+			data:    "A",
+			format:  FormatEnv,
+			wantErr: true,
+		},
+		{
+			name:    "env unterminated",
+			data:    "A=\"x",
+			format:  FormatEnv,
+			wantErr: true,
+		},
+		{
+			name:   "query",
+			data:   "?a=1&b=x%20y&c&d=1&d=2&e[f]=3&e[g][]=4",
+			format: FormatQuery,
+			want:   map[string]any{"a": "1", "b": "x y", "c": "", "d": []any{"1", "2"}, "e": map[string]any{"f": "3", "g": []any{"4"}}},
+		},
+		{
+			name:    "query conflict",
+			data:    "a=1&a[b]=2",
+			format:  FormatQuery,
+			wantErr: true,
+		},
+		{
+			name:    "query empty key",
+			data:    "=x&a=1",
+			format:  FormatQuery,
+			wantErr: true,
+		},
+		{
+			name:    "query empty bracket key",
+			data:    "[]=x",
+			format:  FormatQuery,
+			wantErr: true,
+		},
+		{
+			name:    "query empty list key",
+			data:    "a=1&=x&=y",
+			format:  FormatQuery,
+			wantErr: true,
+		},
+		{
+			name:    "query empty middle key",
+			data:    "a[][b]=1",
+			format:  FormatQuery,
+			wantErr: true,
+		},
+		{
+			name:    "query invalid escape",
+			data:    "a=%zz",
+			format:  FormatQuery,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package mapz
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	flattenEscape  = `\`
+	flattenPathSep = "\x00" //Internal path join, can't use sep because unescaped key may contain it
+)
+
+// Flatten convert nested maps and slices to single level map with keys joined by sep e.g. a.b.0.c
+//
+// Key containing sep or \ is escaped with \ so Unflatten can rebuild it. Empty map or slice is kept as value.
+func Flatten(m map[string]any, sep string) map[string]any {
+	out := make(map[string]any)
+	for k, v := range m {
+		flatten(out, escapeKey(k, sep), v, sep)
+	}
+	return out
+}
+
+func flatten(out map[string]any, prefix string, v any, sep string) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String || rv.Len() == 0 {
+			break
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			flatten(out, prefix+sep+escapeKey(iter.Key().String(), sep), iter.Value().Interface(), sep)
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 || rv.Len() == 0 { //Keep byte slice as value
+			break
+		}
+		for i := 0; i < rv.Len(); i++ {
+			flatten(out, prefix+sep+strconv.Itoa(i), rv.Index(i).Interface(), sep)
+		}
+		return
+	}
+	out[prefix] = v
+}
+
+// Unflatten rebuild nested map from keys created by Flatten.
+//
+// A level is rebuilt as []any when its keys are exactly 0..n-1, otherwise map[string]any. Root is always a map.
+func Unflatten(m map[string]any, sep string) (map[string]any, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) //Need sort in golang for stable error
+	root := map[string]any{}
+	leaf := map[string]bool{}
+	for _, k := range keys {
+		parts := splitKey(k, sep)
+		cur := root
+		for i, p := range parts[:len(parts)-1] {
+			path := strings.Join(parts[:i+1], flattenPathSep)
+			if leaf[path] {
+				return nil, fmt.Errorf("key %q conflicts with value at %q", k, strings.Join(parts[:i+1], sep))
+			}
+			next, ok := cur[p].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				cur[p] = next
+			}
+			cur = next
+		}
+		last := parts[len(parts)-1]
+		if _, exists := cur[last]; exists {
+			return nil, fmt.Errorf("key %q conflicts with nested keys", k)
+		}
+		cur[last] = m[k]
+		leaf[strings.Join(parts, flattenPathSep)] = true
+	}
+	for k, v := range root {
+		root[k] = denseToSlice(v, leaf, k)
+	}
+	return root, nil
+}
+
+func denseToSlice(v any, leaf map[string]bool, path string) any {
+	if leaf[path] {
+		return v
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, v1 := range m {
+		m[k] = denseToSlice(v1, leaf, path+flattenPathSep+k)
+	}
+	out := make([]any, len(m))
+	for k, v1 := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		out[i] = v1
+	}
+	return out
+}
+
+func escapeKey(k string, sep string) string {
+	k = strings.ReplaceAll(k, flattenEscape, flattenEscape+flattenEscape)
+	if sep == "" {
+		return k
+	}
+	return strings.ReplaceAll(k, sep, flattenEscape+sep)
+}
+
+func splitKey(k string, sep string) []string {
+	parts := []string{}
+	var b strings.Builder
+	for i := 0; i < len(k); {
+		switch {
+		case strings.HasPrefix(k[i:], flattenEscape) && i+1 < len(k):
+			i++
+			if sep != "" && strings.HasPrefix(k[i:], sep) {
+				b.WriteString(sep)
+				i += len(sep)
+			} else {
+				b.WriteByte(k[i])
+				i++
+			}
+		case sep != "" && strings.HasPrefix(k[i:], sep):
+			parts = append(parts, b.String())
+			b.Reset()
+			i += len(sep)
+		default:
+			b.WriteByte(k[i])
+			i++
+		}
+	}
+	return append(parts, b.String())
+}
@@ -0,0 +1,163 @@
+package mapz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		m   map[string]any
+		sep string
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]any
+	}{
+		{
+			name: "nested map and slice",
+			args: args{
+				m:   ToMap(`{"a":{"b":[{"c":1},2]},"d":"x","e":null}`),
+				sep: ".",
+			},
+			want: map[string]any{"a.b.0.c": 1.0, "a.b.1": 2.0, "d": "x", "e": nil},
+		},
+		{
+			name: "empty map and slice kept",
+			args: args{
+				m:   map[string]any{"a": map[string]any{}, "b": []any{}},
+				sep: ".",
+			},
+			want: map[string]any{"a": map[string]any{}, "b": []any{}},
+		},
+		{
+			name: "escape sep and escape char",
+			args: args{
+				m:   map[string]any{"a.b": map[string]any{`c\d`: 1}},
+				sep: ".",
+			},
+			want: map[string]any{`a\.b.c\\d`: 1},
+		},
+		{
+			name: "typed map and multi char sep",
+			args: args{
+				m:   map[string]any{"a": map[string][]int{"b": {1, 2}}},
+				sep: "__",
+			},
+			want: map[string]any{"a__b__0": 1, "a__b__1": 2},
+		},
+		{
+			name: "byte slice is value",
+			args: args{
+				m:   map[string]any{"a": []byte("xy")},
+				sep: ".",
+			},
+			want: map[string]any{"a": []byte("xy")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Flatten(tt.args.m, tt.args.sep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		m   map[string]any
+		sep string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "nested map and slice",
+			args: args{
+				m:   map[string]any{"a.b.0.c": 1.0, "a.b.1": 2.0, "d": "x", "e": nil},
+				sep: ".",
+			},
+			want: ToMap(`{"a":{"b":[{"c":1},2]},"d":"x","e":null}`),
+		},
+		{
+			name: "sparse index is map",
+			args: args{
+				m:   map[string]any{"a.0": 1, "a.2": 2},
+				sep: ".",
+			},
+			want: map[string]any{"a": map[string]any{"0": 1, "2": 2}},
+		},
+		{
+			name: "root numeric keys stay map",
+			args: args{
+				m:   map[string]any{"0": 1},
+				sep: ".",
+			},
+			want: map[string]any{"0": 1},
+		},
+		{
+			name: "escaped",
+			args: args{
+				m:   map[string]any{`a\.b.c\\d`: 1},
+				sep: ".",
+			},
+			want: map[string]any{"a.b": map[string]any{`c\d`: 1}},
+		},
+		{
+			name: "empty map value kept",
+			args: args{
+				m:   map[string]any{"a": map[string]any{}},
+				sep: ".",
+			},
+			want: map[string]any{"a": map[string]any{}},
+		},
+		{
+			name: "value conflicts with nested",
+			args: args{
+				m:   map[string]any{"a": 1, "a.b": 2},
+				sep: ".",
+			},
+			wantErr: true,
+		},
+		{
+			name: "nested conflicts with value",
+			args: args{
+				m:   map[string]any{"a.b": 1, "a.b.c": 2},
+				sep: ".",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unflatten(tt.args.m, tt.args.sep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unflatten() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unflatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	t.Parallel()
+	in := ToMap(`{"a.b":{"c/d":[1,{"e\\f":true}]},"g":{"h":null},"i":[]}`)
+	for _, sep := range []string{".", "/", "::"} {
+		got, err := Unflatten(Flatten(in, sep), sep)
+		if err != nil {
+			t.Fatalf("Unflatten() sep %q error = %v", sep, err)
+		}
+		if !reflect.DeepEqual(got, in) {
+			t.Errorf("Unflatten(Flatten()) sep %q = %v, want %v", sep, got, in)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package mapz
+
+import (
+	"sort"
+
+	"github.com/zev-zakaryan/go-util/clonez"
+)
+
+// Sortable is type that support < operator
+type Sortable interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
+}
+
+// Less is natural order of Sortable, use as less of Keys/Values e.g. Keys(m, Less[string])
+func Less[T Sortable](a, b T) bool {
+	return a < b
+}
+
+// Keys return keys of m, sorted by less if specified otherwise in random order as map iteration.
+func Keys[K comparable, V any](m map[K]V, less ...func(a, b K) bool) []K {
+	out := make([]K, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	if len(less) > 0 {
+		sort.Slice(out, func(i, j int) bool { return less[0](out[i], out[j]) })
+	}
+	return out
+}
+
+// Values return values of m, sorted by less if specified otherwise in random order as map iteration.
+func Values[K comparable, V any](m map[K]V, less ...func(a, b V) bool) []V {
+	out := make([]V, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	if len(less) > 0 {
+		sort.Slice(out, func(i, j int) bool { return less[0](out[i], out[j]) })
+	}
+	return out
+}
+
+// Filter return new map of entries that keep return true.
+func Filter[K comparable, V any](m map[K]V, keep func(k K, v V) bool) map[K]V {
+	out := make(map[K]V)
+	for k, v := range m {
+		if keep(k, v) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Partition split m into entries that match return true and the rest.
+func Partition[K comparable, V any](m map[K]V, match func(k K, v V) bool) (matched map[K]V, rest map[K]V) {
+	matched, rest = make(map[K]V), make(map[K]V)
+	for k, v := range m {
+		if match(k, v) {
+			matched[k] = v
+		} else {
+			rest[k] = v
+		}
+	}
+	return
+}
+
+// MapKeys return new map with keys transformed by f. If f return the same key for many entries, which value is kept is undefined.
+func MapKeys[K comparable, V any, K2 comparable](m map[K]V, f func(k K, v V) K2) map[K2]V {
+	out := make(map[K2]V, len(m))
+	for k, v := range m {
+		out[f(k, v)] = v
+	}
+	return out
+}
+
+// MapValues return new map with values transformed by f.
+func MapValues[K comparable, V any, V2 any](m map[K]V, f func(k K, v V) V2) map[K]V2 {
+	out := make(map[K]V2, len(m))
+	for k, v := range m {
+		out[k] = f(k, v)
+	}
+	return out
+}
+
+// Invert swap keys and values.
+//
+// Value shared by many keys is not in out, it's reported in collisions with all of its keys (random order).
+func Invert[K comparable, V comparable](m map[K]V) (out map[V]K, collisions map[V][]K) {
+	out = make(map[V]K, len(m))
+	collisions = make(map[V][]K)
+	for k, v := range m {
+		if ks, ok := collisions[v]; ok {
+			collisions[v] = append(ks, k)
+			continue
+		}
+		if k0, ok := out[v]; ok {
+			collisions[v] = []K{k0, k}
+			delete(out, v)
+			continue
+		}
+		out[v] = k
+	}
+	return
+}
+
+// GroupBy group slice items by key, items in each group keep slice order.
+func GroupBy[T any, K comparable](s []T, key func(item T) K) map[K][]T {
+	out := make(map[K][]T)
+	for _, item := range s {
+		k := key(item)
+		out[k] = append(out[k], item)
+	}
+	return out
+}
+
+// PickKeys return new map of only specified keys, missing key is ignored.
+func PickKeys[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	out := make(map[K]V, len(keys))
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// OmitKeys return new map without specified keys.
+func OmitKeys[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	omit := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		omit[k] = struct{}{}
+	}
+	out := make(map[K]V, len(m))
+	for k, v := range m {
+		if _, ok := omit[k]; !ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// EqualFunc report whether a and b have the same keys and eq is true for every pair of values.
+func EqualFunc[K comparable, V1 any, V2 any](a map[K]V1, b map[K]V2, eq func(v1 V1, v2 V2) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v1 := range a {
+		if v2, ok := b[k]; !ok || !eq(v1, v2) {
+			return false
+		}
+	}
+	return true
+}
+
+// DeepClone return deep copy of m, nested maps, slices, pointers and structs are copied too. See clonez.Deep.
+func DeepClone[K comparable, V any](m map[K]V) map[K]V {
+	return clonez.Deep(m)
+}
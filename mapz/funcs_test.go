@@ -0,0 +1,176 @@
+package mapz
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"b": 1, "c": 3, "a": 2}
+	if got, want := Keys(m, Less[string]), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if got, want := Keys(m, func(a, b string) bool { return a > b }), []string{"c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() desc = %v, want %v", got, want)
+	}
+	got := Keys(m)
+	sort.Strings(got)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() unsorted = %v, want %v", got, want)
+	}
+	if got := Keys(map[string]int(nil)); len(got) != 0 {
+		t.Errorf("Keys() nil = %v", got)
+	}
+}
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"b": 1, "c": 3, "a": 2}
+	if got, want := Values(m, Less[int]), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+	got := Values(m)
+	sort.Ints(got)
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() unsorted = %v, want %v", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	got := Filter(m, func(_ string, v int) bool { return v%2 == 0 })
+	if want := map[string]int{"b": 2, "d": 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	matched, rest := Partition(m, func(k string, _ int) bool { return k != "b" })
+	if want := map[string]int{"a": 1, "c": 3}; !reflect.DeepEqual(matched, want) {
+		t.Errorf("Partition() matched = %v, want %v", matched, want)
+	}
+	if want := map[string]int{"b": 2}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("Partition() rest = %v, want %v", rest, want)
+	}
+}
+
+func TestMapKeys(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"a": 1, "b": 2}
+	got := MapKeys(m, func(k string, _ int) string { return strings.ToUpper(k) })
+	if want := map[string]int{"A": 1, "B": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"a": 1, "b": 2}
+	got := MapValues(m, func(k string, v int) string { return strings.Repeat(k, v) })
+	if want := map[string]string{"a": "a", "b": "bb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapValues() = %v, want %v", got, want)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		m              map[string]int
+		want           map[int]string
+		wantCollisions map[int][]string
+	}{
+		{
+			name:           "no collision",
+			m:              map[string]int{"a": 1, "b": 2},
+			want:           map[int]string{1: "a", 2: "b"},
+			wantCollisions: map[int][]string{},
+		},
+		{
+			name:           "collision",
+			m:              map[string]int{"a": 1, "b": 2, "c": 1, "d": 1},
+			want:           map[int]string{2: "b"},
+			wantCollisions: map[int][]string{1: {"a", "c", "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, collisions := Invert(tt.m)
+			for _, ks := range collisions {
+				sort.Strings(ks)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Invert() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(collisions, tt.wantCollisions) {
+				t.Errorf("Invert() collisions = %v, want %v", collisions, tt.wantCollisions)
+			}
+		})
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+	s := []string{"apple", "bob", "avocado", "cat", "banana"}
+	got := GroupBy(s, func(item string) byte { return item[0] })
+	want := map[byte][]string{'a': {"apple", "avocado"}, 'b': {"bob", "banana"}, 'c': {"cat"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy() = %v, want %v", got, want)
+	}
+}
+
+func TestPickKeys(t *testing.T) {
+	t.Parallel()
+	m := map[string]any{"a": 1, "b": nil, "c": 3}
+	if got, want := PickKeys(m, "a", "b", "x"), map[string]any{"a": 1, "b": nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PickKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestOmitKeys(t *testing.T) {
+	t.Parallel()
+	m := map[string]any{"a": 1, "b": nil, "c": 3}
+	if got, want := OmitKeys(m, "a", "b", "x"), map[string]any{"c": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OmitKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	t.Parallel()
+	eq := func(v1 int, v2 string) bool { return strings.Repeat("x", v1) == v2 }
+	tests := []struct {
+		name string
+		a    map[string]int
+		b    map[string]string
+		want bool
+	}{
+		{name: "equal", a: map[string]int{"a": 1, "b": 2}, b: map[string]string{"a": "x", "b": "xx"}, want: true},
+		{name: "both empty", a: nil, b: map[string]string{}, want: true},
+		{name: "different value", a: map[string]int{"a": 1}, b: map[string]string{"a": "xx"}, want: false},
+		{name: "different key", a: map[string]int{"a": 1}, b: map[string]string{"b": "x"}, want: false},
+		{name: "different len", a: map[string]int{"a": 1}, b: map[string]string{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualFunc(tt.a, tt.b, eq); got != tt.want {
+				t.Errorf("EqualFunc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeepClone(t *testing.T) {
+	t.Parallel()
+	m := map[string][]int{"a": {1}}
+	c := DeepClone(m)
+	c["a"][0] = 2
+	if m["a"][0] != 1 {
+		t.Errorf("DeepClone() share memory, m = %v", m)
+	}
+}
@@ -0,0 +1,433 @@
+package mapz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError is parse error with position, Line and Column (in runes) are from 1.
+type SyntaxError struct {
+	Format Format
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v line %d column %d: %v", e.Format, e.Line, e.Column, e.Msg)
+}
+
+type json5Parser struct {
+	s         string
+	pos       int
+	useNumber bool
+	strict    bool
+}
+
+// decodeJson5 parse JSON5 https://json5.org, superset of json for human-edited files:
+// comments, trailing commas, single-quoted and multi-line strings, unquoted keys, hex numbers, leading/trailing decimal point, + sign, Infinity and NaN.
+//
+// Infinity and NaN are float64 even with OptUseNumber. Duplicate key is error with OptStrict, otherwise the last one wins.
+func decodeJson5(s string, opts map[Option]struct{}) (any, error) {
+	p := &json5Parser{s: strings.TrimPrefix(s, "\ufeff")}
+	_, p.useNumber = opts[OptUseNumber]
+	_, p.strict = opts[OptStrict]
+	if err := p.ws(); err != nil {
+		return nil, err
+	}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.ws(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q after top-level value", p.peekRune())
+	}
+	return v, nil
+}
+
+func (p *json5Parser) errorf(format string, args ...any) error {
+	line, col := 1, 1
+	for _, r := range p.s[:p.pos] {
+		if r == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return &SyntaxError{Format: FormatJson5, Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *json5Parser) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(p.s[p.pos:])
+	return r
+}
+
+// ws skip white spaces and comments
+func (p *json5Parser) ws() error {
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		switch {
+		case unicode.IsSpace(r) || r == '\ufeff':
+			p.pos += size
+		case strings.HasPrefix(p.s[p.pos:], "//"):
+			if i := strings.IndexAny(p.s[p.pos:], "\n\u2028\u2029"); i >= 0 {
+				p.pos += i
+			} else {
+				p.pos = len(p.s)
+			}
+		case strings.HasPrefix(p.s[p.pos:], "/*"):
+			i := strings.Index(p.s[p.pos+2:], "*/")
+			if i < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += i + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *json5Parser) value() (any, error) {
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch c := p.s[p.pos]; {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"' || c == '\'':
+		return p.string()
+	case c == '-' || c == '+' || c == '.' || c >= '0' && c <= '9' || c == 'I' || c == 'N':
+		return p.number()
+	}
+	for lit, v := range map[string]any{"true": true, "false": false, "null": nil} {
+		if strings.HasPrefix(p.s[p.pos:], lit) && !p.isIdentifierAt(p.pos+len(lit)) {
+			p.pos += len(lit)
+			return v, nil
+		}
+	}
+	return nil, p.errorf("unexpected %q", p.peekRune())
+}
+
+func (p *json5Parser) object() (any, error) {
+	p.pos++ //{
+	out := map[string]any{}
+	for {
+		if err := p.ws(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '}' {
+			p.pos++
+			return out, nil
+		}
+		keyPos := p.pos
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.ws(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.s) || p.s[p.pos] != ':' {
+			return nil, p.errorf("expect : after key %q", key)
+		}
+		p.pos++
+		if err := p.ws(); err != nil {
+			return nil, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := out[key]; exists && p.strict {
+			p.pos = keyPos
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		out[key] = v
+		if done, err := p.next('}'); err != nil || done {
+			return out, err
+		}
+	}
+}
+
+func (p *json5Parser) array() (any, error) {
+	p.pos++ //[
+	out := []any{}
+	for {
+		if err := p.ws(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == ']' {
+			p.pos++
+			return out, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		if done, err := p.next(']'); err != nil || done {
+			return out, err
+		}
+	}
+}
+
+// next consume , or closing after member, done if closing. Trailing comma is handled by the caller loop.
+func (p *json5Parser) next(closing byte) (done bool, err error) {
+	if err := p.ws(); err != nil {
+		return false, err
+	}
+	if p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+			return false, nil
+		case closing:
+			p.pos++
+			return true, nil
+		}
+	}
+	if p.pos >= len(p.s) {
+		return false, p.errorf("unexpected end of input, expect , or %c", closing)
+	}
+	return false, p.errorf("expect , or %c, got %q", closing, p.peekRune())
+}
+
+func (p *json5Parser) key() (string, error) {
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		return p.string()
+	}
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !isJson5IdentifierRune(r, p.pos == start) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		if p.pos >= len(p.s) {
+			return "", p.errorf("unexpected end of input, expect key")
+		}
+		return "", p.errorf("invalid key start %q", p.peekRune())
+	}
+	return p.s[start:p.pos], nil
+}
+
+func isJson5IdentifierRune(r rune, first bool) bool {
+	if unicode.IsLetter(r) || r == '$' || r == '_' || unicode.Is(unicode.Nl, r) {
+		return true
+	}
+	return !first && (unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc) || r == '\u200c' || r == '\u200d')
+}
+
+func (p *json5Parser) isIdentifierAt(pos int) bool {
+	if pos >= len(p.s) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(p.s[pos:])
+	return isJson5IdentifierRune(r, false)
+}
+
+func (p *json5Parser) string() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\n' || c == '\r':
+			return "", p.errorf("unescaped line break in string")
+		case c == '\\':
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+			sb.WriteRune(r)
+			p.pos += size
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *json5Parser) escape(sb *strings.Builder) error {
+	p.pos++ //Backslash
+	if p.pos >= len(p.s) {
+		return p.errorf("unterminated string")
+	}
+	r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+	p.pos += size
+	switch r {
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '0':
+		if p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			return p.errorf("octal escape is not allowed")
+		}
+		sb.WriteByte(0)
+	case 'x', 'u':
+		n := 2
+		if r == 'u' {
+			n = 4
+		}
+		code, err := p.hexRune(n)
+		if err != nil {
+			return err
+		}
+		if utf16IsHighSurrogate(code) && strings.HasPrefix(p.s[p.pos:], `\u`) {
+			start := p.pos
+			p.pos += 2
+			if low, err := p.hexRune(4); err == nil && low >= 0xdc00 && low < 0xe000 {
+				code = (code-0xd800)<<10 + (low - 0xdc00) + 0x10000
+			} else {
+				p.pos = start //Not a pair, decode the second escape by itself
+			}
+		}
+		sb.WriteRune(code) //Unpaired surrogate is U+FFFD as encoding/json
+	case '\r':
+		if p.pos < len(p.s) && p.s[p.pos] == '\n' {
+			p.pos++
+		}
+	case '\n', '\u2028', '\u2029': //Line continuation
+	default:
+		if r >= '1' && r <= '9' {
+			return p.errorf("invalid escape \\%c", r)
+		}
+		sb.WriteRune(r) //e.g. \' \" \\ \/ and any other character as itself
+	}
+	return nil
+}
+
+func utf16IsHighSurrogate(r rune) bool {
+	return r >= 0xd800 && r < 0xdc00
+}
+
+func (p *json5Parser) hexRune(n int) (rune, error) {
+	if p.pos+n > len(p.s) {
+		return 0, p.errorf("invalid hex escape")
+	}
+	code, err := strconv.ParseUint(p.s[p.pos:p.pos+n], 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid hex escape %q", p.s[p.pos:p.pos+n])
+	}
+	p.pos += n
+	return rune(code), nil
+}
+
+func (p *json5Parser) number() (any, error) {
+	start := p.pos
+	sign := ""
+	if c := p.s[p.pos]; c == '+' || c == '-' {
+		if c == '-' {
+			sign = "-"
+		}
+		p.pos++
+	}
+	rest := p.s[p.pos:]
+	for lit, f := range map[string]float64{"Infinity": math.Inf(1), "NaN": math.NaN()} {
+		if strings.HasPrefix(rest, lit) && !p.isIdentifierAt(p.pos+len(lit)) {
+			p.pos += len(lit)
+			return withSign(sign, f), nil
+		}
+	}
+	if strings.HasPrefix(rest, "0x") || strings.HasPrefix(rest, "0X") {
+		p.pos += 2
+		digitStart := p.pos
+		for p.pos < len(p.s) && strings.IndexByte("0123456789abcdefABCDEF", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		n, err := strconv.ParseUint(p.s[digitStart:p.pos], 16, 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid hex number")
+		}
+		if p.useNumber {
+			return json.Number(sign + strconv.FormatUint(n, 10)), nil
+		}
+		return withSign(sign, float64(n)), nil
+	}
+	digits := 0
+	intStart := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+		digits++
+	}
+	if p.pos-intStart > 1 && p.s[intStart] == '0' {
+		p.pos = start
+		return nil, p.errorf("leading zero is not allowed")
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == '.' {
+		p.pos++
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+			digits++
+		}
+	}
+	if digits == 0 {
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	if p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		p.pos++
+		if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		expStart := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos == expStart {
+			p.pos = start
+			return nil, p.errorf("invalid number exponent")
+		}
+	}
+	if p.isIdentifierAt(p.pos) {
+		return nil, p.errorf("unexpected %q after number", p.peekRune())
+	}
+	lit := strings.TrimPrefix(p.s[start:p.pos], "+")
+	if p.useNumber { //Normalize to json number e.g. .5 is 0.5 and 5. is 5
+		lit = strings.Replace(strings.Replace(lit, ".e", "e", 1), ".E", "E", 1)
+		lit = strings.TrimSuffix(lit, ".")
+		if strings.HasPrefix(strings.TrimPrefix(lit, "-"), ".") {
+			lit = strings.Replace(lit, ".", "0.", 1)
+		}
+		return json.Number(lit), nil
+	}
+	f, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number %v", lit)
+	}
+	return f, nil
+}
+
+func withSign(sign string, f float64) float64 {
+	if sign == "-" {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,132 @@
+package mapz
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToMapELenient(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		data string
+		opts []Option
+		want map[string]any
+	}{
+		{
+			name: "plain json",
+			data: `{"a":[1,"x",true,null,{"b":-1.5e2}]}`,
+			want: map[string]any{"a": []any{1.0, "x", true, nil, map[string]any{"b": -150.0}}},
+		},
+		{
+			name: "comments and trailing commas",
+			data: "// config\n{\n  /* block\n  comment */ \"a\": 1, // inline\n  \"b\": [1, 2,],\n}\n/* end */",
+			want: map[string]any{"a": 1.0, "b": []any{1.0, 2.0}},
+		},
+		{
+			name: "unquoted keys and single quotes",
+			data: `{ $id: 'it\'s', _x1: "say \"hi\"", 'k y': '"', ünï: 1 }`,
+			want: map[string]any{"$id": "it's", "_x1": `say "hi"`, "k y": `"`, "ünï": 1.0},
+		},
+		{
+			name: "numbers",
+			data: `{hex: 0xFF, neg: -0x10, lead: .5, trail: 5., plus: +1, exp: 1E3, inf: -Infinity, zero: 0}`,
+			want: map[string]any{"hex": 255.0, "neg": -16.0, "lead": 0.5, "trail": 5.0, "plus": 1.0, "exp": 1000.0, "inf": math.Inf(-1), "zero": 0.0},
+		},
+		{
+			name: "use number",
+			data: `{hex: 0xff, lead: -.5, trail: 5.e1, plus: +7, big: 12345678901234567890}`,
+			opts: []Option{OptUseNumber},
+			want: map[string]any{"hex": json.Number("255"), "lead": json.Number("-0.5"), "trail": json.Number("5e1"), "plus": json.Number("7"), "big": json.Number("12345678901234567890")},
+		},
+		{
+			name: "escapes and line continuation",
+			data: "{s: '\\x41\\u00e9\\uD83D\\uDE00\\t\\v\\0\\q line\\\n2'}",
+			want: map[string]any{"s": "Aé😀\t\v\x00q line2"},
+		},
+		{
+			name: "unpaired surrogates",
+			data: `{s: '\ud800\u0041', t: '\ud800\ud800\udc00', u: '\udc00x', v: '\ud800\x41'}`,
+			want: map[string]any{"s": "\ufffdA", "t": "\ufffd\U00010000", "u": "\ufffdx", "v": "\ufffdA"},
+		},
+		{
+			name: "duplicate last wins",
+			data: `{a: 1, a: 2}`,
+			want: map[string]any{"a": 2.0},
+		},
+		{
+			name: "empty",
+			data: "\ufeff{ /* nothing */ }",
+			want: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMapE(tt.data, append(tt.opts, OptLenient)...)
+			if err != nil {
+				t.Fatalf("ToMapE() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToMapE() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+	if got := ToMap("{a: 1}"); got != nil {
+		t.Errorf("ToMap() = %v, want nil without OptLenient", got)
+	}
+	if got, err := ToMapE(strings.NewReader("{a: NaN}"), OptLenient); err != nil || !math.IsNaN(got["a"].(float64)) {
+		t.Errorf("ToMapE() reader = %v, %v", got, err)
+	}
+	if got, err := Decode("{a: 'x',}", FormatJson5); err != nil || !reflect.DeepEqual(got, map[string]any{"a": "x"}) {
+		t.Errorf("Decode() = %v, %v", got, err)
+	}
+}
+
+func TestToMapELenientError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		data   string
+		opts   []Option
+		line   int
+		column int
+		msg    string
+	}{
+		{name: "unterminated comment", data: "{\n  a: 1 /* x", line: 2, column: 8, msg: "unterminated comment"},
+		{name: "missing colon", data: "{\n  a 1}", line: 2, column: 5, msg: `expect : after key "a"`},
+		{name: "missing comma", data: "{a: 1\n b: 2}", line: 2, column: 2, msg: `expect , or }, got 'b'`},
+		{name: "double comma", data: "[1,,2]", line: 1, column: 4, msg: `unexpected ','`},
+		{name: "unterminated string", data: "{a: 'x", line: 1, column: 7, msg: "unterminated string"},
+		{name: "line break in string", data: "{a: 'x\ny'}", line: 1, column: 7, msg: "unescaped line break in string"},
+		{name: "column in runes", data: "{é: ü,}", line: 1, column: 5, msg: `unexpected 'ü'`},
+		{name: "leading zero", data: "{a: 01}", line: 1, column: 5, msg: "leading zero is not allowed"},
+		{name: "bad hex escape", data: `{a: '\xZZ'}`, line: 1, column: 8, msg: `invalid hex escape "ZZ"`},
+		{name: "literal prefix", data: "{a: nullx}", line: 1, column: 5, msg: `unexpected 'n'`},
+		{name: "invalid key", data: "{1a: 1}", line: 1, column: 2, msg: `invalid key start '1'`},
+		{name: "trailing data", data: "{}\n// ok\n{}", line: 3, column: 1, msg: `unexpected '{' after top-level value`},
+		{name: "end of input", data: "{a: [1,", line: 1, column: 8, msg: "unexpected end of input"},
+		{name: "strict duplicate", data: "{a: 1,\n a: 2}", opts: []Option{OptStrict}, line: 2, column: 2, msg: `duplicate key "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToMapE(tt.data, append(tt.opts, OptLenient)...)
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("ToMapE() error = %v, want *SyntaxError", err)
+			}
+			if se.Line != tt.line || se.Column != tt.column || se.Msg != tt.msg {
+				t.Errorf("ToMapE() error = %v, want line %d column %d: %v", err, tt.line, tt.column, tt.msg)
+			}
+		})
+	}
+	if _, err := ToMapE("[1]", OptLenient); err == nil || errors.As(err, new(*SyntaxError)) {
+		t.Errorf("ToMapE() error = %v, want non-object error", err)
+	}
+	if got := (&SyntaxError{Format: FormatJson5, Line: 2, Column: 3, Msg: "x"}).Error(); got != "json5 line 2 column 3: x" {
+		t.Errorf("Error() = %v", got)
+	}
+}
@@ -1,11 +1,24 @@
 package mapz
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/zev-zakaryan/go-util/conv"
 )
 
+type Option string
+
+const (
+	OptStrict    Option = "strict"     //Reject duplicate keys and any data after top-level value
+	OptUseNumber Option = "use number" //Decode number as json.Number instead of float64
+	OptLenient   Option = "lenient"    //Accept JSON5 e.g. comments, trailing commas, single-quoted strings, unquoted keys and hex numbers
+)
+
 func Join[TKey comparable, TVal comparable](out map[TKey]TVal, excludeEmpty bool, in ...map[TKey]TVal) {
 	var empty TVal
 	for _, in1 := range in {
@@ -27,36 +40,137 @@ func JoinAny[TKey comparable, TVal any](out map[TKey]TVal, in ...map[TKey]TVal)
 	}
 }
 
+// ToMap convert json string, []byte, io.Reader or any object (via json) to map. Return nil if fail, use ToMapE to get error.
 func ToMap(obj interface{}) map[string]interface{} {
-	var out map[string]interface{}
-	var objJ []byte
-	switch v := obj.(type) {
-	case string:
-		objJ = []byte(v)
-	case []byte:
-		objJ = v
-	default:
-		objJ, _ = json.Marshal(obj)
-	}
-	json.Unmarshal(objJ, &out)
+	out, _ := ToMapE(obj)
 	return out
 }
 
+// ToMapE is ToMap with error e.g. invalid json or top-level value is not an object.
+//
+// io.Reader is decoded only for its first json value unless OptStrict. Use OptUseNumber to get json.Number instead of float64.
+// Use OptLenient for hand-edited JSON5, error is *SyntaxError with line and column. Reader is read whole with OptLenient.
+func ToMapE(obj any, opts ...Option) (map[string]any, error) {
+	v, err := decodeJson(obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("fail cast to map, json top-level value is %T", v)
+	}
+	return out, nil
+}
+
+// ToStringMap is ToMap with values converted by conv.ToForce[string]. Return empty map if fail, use ToStringMapE to get error.
 func ToStringMap(obj interface{}) map[string]string {
-	var outI map[string]interface{}
-	var objJ []byte
+	out, err := ToStringMapE(obj)
+	if err != nil {
+		return make(map[string]string)
+	}
+	return out
+}
+
+// ToStringMapE is ToStringMap with error, see ToMapE for opts.
+func ToStringMapE(obj any, opts ...Option) (map[string]string, error) {
+	outI, err := ToMapE(obj, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(outI))
+	for k, v := range outI {
+		out[k] = conv.ToForce[string](v)
+	}
+	return out, nil
+}
+
+func decodeJson(obj any, opts []Option) (any, error) {
+	optsMap := make(map[Option]struct{}, 0)
+	for _, opt := range opts {
+		optsMap[opt] = struct{}{}
+	}
+	_, strict := optsMap[OptStrict]
+	var r io.Reader
+	whole := true //Must be only one json value
 	switch v := obj.(type) {
 	case string:
-		objJ = []byte(v)
+		r = strings.NewReader(v)
 	case []byte:
-		objJ = v
+		r = bytes.NewReader(v)
+	case io.Reader:
+		r = v
+		whole = strict
 	default:
-		objJ, _ = json.Marshal(obj)
+		objJ, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(objJ)
 	}
-	json.Unmarshal(objJ, &outI)
-	out := make(map[string]string)
-	for k, v := range outI {
-		out[k] = conv.ToForce[string](v)
+	if _, ok := optsMap[OptLenient]; ok {
+		objJ, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return decodeJson5(string(objJ), optsMap)
 	}
-	return out
+	if strict {
+		objJ, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkDuplicateKeys(json.NewDecoder(bytes.NewReader(objJ)), ""); err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(objJ)
+	}
+	dec := json.NewDecoder(r)
+	if _, ok := optsMap[OptUseNumber]; ok {
+		dec.UseNumber()
+	}
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	if whole {
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, fmt.Errorf("invalid json: data after top-level value")
+		}
+	}
+	return out, nil
+}
+
+func checkDuplicateKeys(dec *json.Decoder, path string) error {
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	switch t {
+	case json.Delim('{'):
+		seen := make(map[string]struct{})
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("invalid json: %w", err)
+			}
+			ks := k.(string)
+			if _, ok := seen[ks]; ok {
+				return fmt.Errorf("invalid json: duplicate key %q at %q", ks, path)
+			}
+			seen[ks] = struct{}{}
+			if err = checkDuplicateKeys(dec, path+"/"+escapePointer(ks)); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err = checkDuplicateKeys(dec, path+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	_, err = dec.Token() //Closing delimiter
+	return err
 }
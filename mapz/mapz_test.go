@@ -1,7 +1,10 @@
 package mapz
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -310,3 +313,127 @@ func TestToStringMap(t *testing.T) {
 		})
 	}
 }
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read fail")
+}
+
+func TestToMapE(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		obj  any
+		opts []Option
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "from json string",
+			args: args{obj: `{"x":{"y":[1]}}`},
+			want: map[string]any{"x": map[string]any{"y": []any{1.0}}},
+		},
+		{
+			name: "from reader",
+			args: args{obj: strings.NewReader(`{"x":1}`)},
+			want: map[string]any{"x": 1.0},
+		},
+		{
+			name: "reader read only first value",
+			args: args{obj: strings.NewReader(`{"x":1} {"x":2}`)},
+			want: map[string]any{"x": 1.0},
+		},
+		{
+			name:    "reader strict reject trailing data",
+			args:    args{obj: strings.NewReader(`{"x":1} {"x":2}`), opts: []Option{OptStrict}},
+			wantErr: true,
+		},
+		{
+			name:    "reader error",
+			args:    args{obj: errReader{}},
+			wantErr: true,
+		},
+		{
+			name: "use number",
+			args: args{obj: []byte(`{"x":12345678901234567890}`), opts: []Option{OptUseNumber}},
+			want: map[string]any{"x": json.Number("12345678901234567890")},
+		},
+		{
+			name: "duplicate key last win",
+			args: args{obj: `{"x":1,"x":2}`},
+			want: map[string]any{"x": 2.0},
+		},
+		{
+			name:    "strict duplicate key",
+			args:    args{obj: `{"a":[{"x":1,"x":2}]}`, opts: []Option{OptStrict}},
+			wantErr: true,
+		},
+		{
+			name: "strict same key in different object",
+			args: args{obj: `{"a":{"x":1},"b":{"x":2}}`, opts: []Option{OptStrict}},
+			want: map[string]any{"a": map[string]any{"x": 1.0}, "b": map[string]any{"x": 2.0}},
+		},
+		{
+			name:    "trailing data",
+			args:    args{obj: `{"x":1} x`},
+			wantErr: true,
+		},
+		{
+			name: "trailing space",
+			args: args{obj: "{\"x\":1} \n"},
+			want: map[string]any{"x": 1.0},
+		},
+		{
+			name:    "invalid json",
+			args:    args{obj: `{"x":`},
+			wantErr: true,
+		},
+		{
+			name:    "not object",
+			args:    args{obj: `[1,2]`},
+			wantErr: true,
+		},
+		{
+			name:    "null",
+			args:    args{obj: `null`},
+			wantErr: true,
+		},
+		{
+			name:    "marshal error",
+			args:    args{obj: map[string]any{"f": func() {}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMapE(tt.args.obj, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToMapE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToMapE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToStringMapE(t *testing.T) {
+	t.Parallel()
+	got, err := ToStringMapE(strings.NewReader(`{"x":true,"z":12345678901234567890}`), OptUseNumber)
+	if err != nil {
+		t.Fatalf("ToStringMapE() error = %v", err)
+	}
+	if want := map[string]string{"x": "true", "z": "12345678901234567890"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToStringMapE() = %v, want %v", got, want)
+	}
+	if _, err := ToStringMapE(`"x"`); err == nil {
+		t.Errorf("ToStringMapE() expect error")
+	}
+	if got := ToStringMap(`"x"`); got == nil || len(got) != 0 {
+		t.Errorf("ToStringMap() = %v, want empty map", got)
+	}
+}
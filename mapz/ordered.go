@@ -0,0 +1,308 @@
+package mapz
+
+import (
+	"bytes"
+	"container/list"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Ordered is map that keep insertion order. Get/Set/Delete are O(1). Zero value is ready to use, not safe for concurrent use.
+// Read methods of nil *Ordered are empty like nil map e.g. when it is reached by conv.GetItems.
+//
+// JSON marshal/unmarshal keep key order, with V=any nested objects are decoded as *Ordered[string, any] so document can be re-emitted unchanged.
+type Ordered[K comparable, V any] struct {
+	m map[K]*list.Element
+	l *list.List
+}
+
+type orderedEntry[K comparable, V any] struct {
+	key K
+	val V
+}
+
+func NewOrdered[K comparable, V any]() *Ordered[K, V] {
+	o := &Ordered[K, V]{}
+	o.init()
+	return o
+}
+
+func (o *Ordered[K, V]) init() {
+	if o.m == nil {
+		o.m = make(map[K]*list.Element)
+		o.l = list.New()
+	}
+}
+
+// Set value of key, existing key keep its position.
+func (o *Ordered[K, V]) Set(key K, val V) {
+	o.init()
+	if e, ok := o.m[key]; ok {
+		e.Value.(*orderedEntry[K, V]).val = val
+		return
+	}
+	o.m[key] = o.l.PushBack(&orderedEntry[K, V]{key: key, val: val})
+}
+
+func (o *Ordered[K, V]) Get(key K) (V, bool) {
+	if o == nil {
+		var zero V
+		return zero, false
+	}
+	if e, ok := o.m[key]; ok {
+		return e.Value.(*orderedEntry[K, V]).val, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (o *Ordered[K, V]) Has(key K) bool {
+	if o == nil {
+		return false
+	}
+	_, ok := o.m[key]
+	return ok
+}
+
+// Delete key, return false if not exists.
+func (o *Ordered[K, V]) Delete(key K) bool {
+	e, ok := o.m[key]
+	if !ok {
+		return false
+	}
+	o.l.Remove(e)
+	delete(o.m, key)
+	return true
+}
+
+func (o *Ordered[K, V]) Len() int {
+	if o == nil {
+		return 0
+	}
+	return len(o.m)
+}
+
+func (o *Ordered[K, V]) Clear() {
+	o.m = nil
+	o.init()
+}
+
+// Keys return keys in insertion order.
+func (o *Ordered[K, V]) Keys() []K {
+	out := make([]K, 0, o.Len())
+	o.Range(func(k K, _ V) bool {
+		out = append(out, k)
+		return true
+	})
+	return out
+}
+
+// Values return values in insertion order.
+func (o *Ordered[K, V]) Values() []V {
+	out := make([]V, 0, o.Len())
+	o.Range(func(_ K, v V) bool {
+		out = append(out, v)
+		return true
+	})
+	return out
+}
+
+// Range call f for each key in insertion order until f return false.
+func (o *Ordered[K, V]) Range(f func(key K, val V) bool) {
+	if o == nil || o.l == nil {
+		return
+	}
+	for e := o.l.Front(); e != nil; e = e.Next() {
+		en := e.Value.(*orderedEntry[K, V])
+		if !f(en.key, en.val) {
+			return
+		}
+	}
+}
+
+// ToMap return plain map, order is lost.
+func (o *Ordered[K, V]) ToMap() map[K]V {
+	out := make(map[K]V, o.Len())
+	o.Range(func(k K, v V) bool {
+		out[k] = v
+		return true
+	})
+	return out
+}
+
+// OrderedKeys implement conv.OrderedGetter so conv.GetItems traverse in insertion order.
+func (o *Ordered[K, V]) OrderedKeys() []string {
+	out := make([]string, 0, o.Len())
+	o.Range(func(k K, _ V) bool {
+		out = append(out, orderedKeyString(k))
+		return true
+	})
+	return out
+}
+
+// OrderedGet implement conv.OrderedGetter.
+func (o *Ordered[K, V]) OrderedGet(key string) (any, bool) {
+	if k, err := parseOrderedKey[K](key); err == nil {
+		v, ok := o.Get(k)
+		return v, ok
+	}
+	var out any
+	var found bool
+	o.Range(func(k K, v V) bool {
+		if orderedKeyString(k) == key {
+			out, found = v, true
+		}
+		return !found
+	})
+	return out, found
+}
+
+func (o *Ordered[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	var err error
+	i := 0
+	o.Range(func(k K, v V) bool {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		i++
+		var bs []byte
+		if bs, err = json.Marshal(orderedKeyString(k)); err != nil {
+			return false
+		}
+		buf.Write(bs)
+		buf.WriteByte(':')
+		if bs, err = json.Marshal(v); err != nil {
+			return false
+		}
+		buf.Write(bs)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (o *Ordered[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	o.Clear()
+	if t == nil {
+		return nil
+	}
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("fail unmarshal %v into %T, expect object", t, o)
+	}
+	var zero V
+	_, isAny := any(&zero).(*any)
+	for dec.More() {
+		if t, err = dec.Token(); err != nil {
+			return err
+		}
+		k, err := parseOrderedKey[K](t.(string))
+		if err != nil {
+			return err
+		}
+		var v V
+		if isAny {
+			var vAny any
+			if vAny, err = decodeOrderedValue(dec); err != nil {
+				return err
+			}
+			v, _ = vAny.(V)
+		} else if err = dec.Decode(&v); err != nil {
+			return err
+		}
+		o.Set(k, v)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func decodeOrderedValue(dec *json.Decoder) (any, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	d, ok := t.(json.Delim)
+	if !ok {
+		return t, nil
+	}
+	switch d {
+	case '{':
+		out := NewOrdered[string, any]()
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			out.Set(k.(string), v)
+		}
+		_, err = dec.Token()
+		return out, err
+	case '[':
+		out := make([]any, 0)
+		for dec.More() {
+			v, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		_, err = dec.Token()
+		return out, err
+	}
+	return nil, fmt.Errorf("unexpected json delimiter %v", d)
+}
+
+func orderedKeyString[K comparable](k K) string {
+	switch kv := any(k).(type) {
+	case string:
+		return kv
+	case encoding.TextMarshaler:
+		if bs, err := kv.MarshalText(); err == nil {
+			return string(bs)
+		}
+	}
+	return fmt.Sprintf("%v", k)
+}
+
+func parseOrderedKey[K comparable](s string) (K, error) {
+	var k K
+	if tu, ok := any(&k).(encoding.TextUnmarshaler); ok {
+		return k, tu.UnmarshalText([]byte(s))
+	}
+	rv := reflect.ValueOf(&k).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return k, err
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return k, err
+		}
+		rv.SetUint(i)
+	default:
+		return k, fmt.Errorf("unsupported key type %T", k)
+	}
+	return k, nil
+}
@@ -0,0 +1,178 @@
+package mapz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+func TestOrdered(t *testing.T) {
+	t.Parallel()
+	var o Ordered[string, int] //Zero value is ready to use
+	o.Set("c", 1)
+	o.Set("a", 2)
+	o.Set("b", 3)
+	o.Set("a", 4) //Existing key keep position
+	if got, want := o.Keys(), []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if got, want := o.Values(), []int{1, 4, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+	if v, ok := o.Get("a"); !ok || v != 4 {
+		t.Errorf("Get() = %v, %v, want 4, true", v, ok)
+	}
+	if v, ok := o.Get("x"); ok || v != 0 {
+		t.Errorf("Get() = %v, %v, want 0, false", v, ok)
+	}
+	if !o.Delete("a") || o.Delete("a") || o.Has("a") || o.Len() != 2 {
+		t.Errorf("Delete() fail, keys %v", o.Keys())
+	}
+	o.Set("a", 5)
+	if got, want := o.Keys(), []string{"c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() after delete = %v, want %v", got, want)
+	}
+	if got, want := o.ToMap(), map[string]int{"c": 1, "b": 3, "a": 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v, want %v", got, want)
+	}
+	n := 0
+	o.Range(func(_ string, _ int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Range() stop fail, called %v", n)
+	}
+	o.Clear()
+	if o.Len() != 0 || len(o.Keys()) != 0 {
+		t.Errorf("Clear() fail, keys %v", o.Keys())
+	}
+}
+
+func TestOrderedJson(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "nested keep order",
+			in:   `{"z":1,"a":{"y":[{"k":true,"b":null}],"x":"s"},"m":[]}`,
+		},
+		{
+			name: "empty",
+			in:   `{}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOrdered[string, any]()
+			if err := json.Unmarshal([]byte(tt.in), o); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			bs, err := json.Marshal(o)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(bs) != tt.in {
+				t.Errorf("json.Marshal() = %v, want %v", string(bs), tt.in)
+			}
+		})
+	}
+
+	typed := NewOrdered[int, []string]()
+	if err := json.Unmarshal([]byte(`{"3":["a"],"1":["b","c"]}`), typed); err != nil {
+		t.Fatalf("json.Unmarshal() typed error = %v", err)
+	}
+	if got, want := typed.Keys(), []int{3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("typed Keys() = %v, want %v", got, want)
+	}
+	if got, _ := typed.Get(1); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("typed Get() = %v", got)
+	}
+	if err := json.Unmarshal([]byte(`{"x":[]}`), typed); err == nil {
+		t.Errorf("json.Unmarshal() expect error for invalid int key")
+	}
+	if err := json.Unmarshal([]byte(`[1]`), typed); err == nil {
+		t.Errorf("json.Unmarshal() expect error for array")
+	}
+}
+
+func TestOrderedGetItems(t *testing.T) {
+	t.Parallel()
+	o := NewOrdered[string, any]()
+	if err := json.Unmarshal([]byte(`{"items":[{"z":1,"a":2},{"y":3,"b":4}],"c":{"value_9":"x","value_1":"y"}}`), o); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		keys string
+		want []any
+	}{
+		{
+			name: "#k document order",
+			keys: "#k",
+			want: []any{"items", "c"},
+		},
+		{
+			name: "nested # document order",
+			keys: "items.#.#",
+			want: []any{1.0, 2.0, 3.0, 4.0},
+		},
+		{
+			name: "nested #k document order",
+			keys: "items.#.#k",
+			want: []any{"z", "a", "y", "b"},
+		},
+		{
+			name: "regex document order",
+			keys: "c.^value_",
+			want: []any{"x", "y"},
+		},
+		{
+			name: "key",
+			keys: "items.1.b",
+			want: []any{4.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conv.GetItems(o, tt.keys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	typed := NewOrdered[int, string]()
+	typed.Set(2, "b")
+	typed.Set(1, "a")
+	if got, want := conv.GetItems(typed, "#k"), []any{"2", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetItems() typed = %v, want %v", got, want)
+	}
+	if got, want := conv.GetItems(typed, "1"), []any{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetItems() typed = %v, want %v", got, want)
+	}
+}
+
+func TestOrderedNil(t *testing.T) {
+	t.Parallel()
+	var o *Ordered[string, any]
+	if o.Len() != 0 || o.Has("a") || len(o.Keys()) != 0 || len(o.OrderedKeys()) != 0 {
+		t.Errorf("nil Ordered should be empty")
+	}
+	if v, ok := o.OrderedGet("a"); ok || v != nil {
+		t.Errorf("OrderedGet() of nil = %v, %v", v, ok)
+	}
+	doc := map[string]any{"o": o, "m": map[string]any(nil)}
+	for _, keys := range []string{"o.a", "m.a"} {
+		if got := conv.GetItems(doc, keys); !reflect.DeepEqual(got, []any{nil}) {
+			t.Errorf("GetItems(%q) = %v, want [nil]", keys, got)
+		}
+	}
+	for _, keys := range []string{"o.#", "o.#k", "o.^a", "m.#"} {
+		if got := conv.GetItems(doc, keys); len(got) != 0 {
+			t.Errorf("GetItems(%q) = %v, want []", keys, got)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package mapz
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// PatchOp is one RFC 6902 JSON Patch operation. Path and From are JSON Pointers (RFC 6901).
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Patch is RFC 6902 JSON Patch document, json.Marshal of it is a valid patch.
+type Patch []PatchOp
+
+// MarshalJSON keep "value" for add/replace/test even if it's null and "from" for move/copy even if it's "" (root)
+func (o PatchOp) MarshalJSON() ([]byte, error) {
+	type plain PatchOp
+	switch o.Op {
+	case OpAdd, OpReplace, OpTest:
+		return json.Marshal(struct {
+			Op    string `json:"op"`
+			Path  string `json:"path"`
+			Value any    `json:"value"`
+		}{o.Op, o.Path, o.Value})
+	case OpMove, OpCopy:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+			From string `json:"from"`
+		}{o.Op, o.Path, o.From})
+	}
+	return json.Marshal(plain{Op: o.Op, Path: o.Path, From: o.From})
+}
+
+// ParsePatch decode RFC 6902 JSON Patch document.
+func ParsePatch(data []byte) (Patch, error) {
+	var p Patch
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid json patch: %w", err)
+	}
+	return p, nil
+}
+
+// Diff return operations to transform a to b. Keys are visited in sorted order so output is stable.
+//
+// Nested map[string]any and []any are compared recursively, other values by reflect.DeepEqual.
+func Diff(a, b map[string]any) Patch {
+	p := Patch{}
+	diffMap(&p, "", a, b)
+	return p
+}
+
+func diffMap(p *Patch, path string, a, b map[string]any) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys) //Need sort in golang for stable iteration order
+	for _, k := range keys {
+		va, inA := a[k]
+		vb, inB := b[k]
+		kp := path + "/" + escapePointer(k)
+		switch {
+		case !inB:
+			*p = append(*p, PatchOp{Op: OpRemove, Path: kp})
+		case !inA:
+			*p = append(*p, PatchOp{Op: OpAdd, Path: kp, Value: vb})
+		default:
+			diffValue(p, kp, va, vb)
+		}
+	}
+}
+
+func diffSlice(p *Patch, path string, a, b []any) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		diffValue(p, path+"/"+strconv.Itoa(i), a[i], b[i])
+	}
+	for i := n; i < len(b); i++ {
+		*p = append(*p, PatchOp{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), Value: b[i]})
+	}
+	for i := len(a) - 1; i >= n; i-- { //Remove from the end so earlier index is still valid
+		*p = append(*p, PatchOp{Op: OpRemove, Path: path + "/" + strconv.Itoa(i)})
+	}
+}
+
+func diffValue(p *Patch, path string, a, b any) {
+	if ma, ok := a.(map[string]any); ok {
+		if mb, ok := b.(map[string]any); ok {
+			diffMap(p, path, ma, mb)
+			return
+		}
+	}
+	if sa, ok := a.([]any); ok {
+		if sb, ok := b.([]any); ok {
+			diffSlice(p, path, sa, sb)
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*p = append(*p, PatchOp{Op: OpReplace, Path: path, Value: b})
+	}
+}
+
+// ApplyPatch apply RFC 6902 JSON Patch to a copy of doc, doc is never modified.
+//
+// Whole patch is applied or error is returned (atomic).
+func ApplyPatch(doc map[string]any, patch Patch) (map[string]any, error) {
+	var out any = cloneJSON(doc)
+	var err error
+	for i, op := range patch {
+		if out, err = applyOp(out, op); err != nil {
+			return nil, fmt.Errorf("patch op %v (%v %v): %w", i, op.Op, op.Path, err)
+		}
+	}
+	outM, ok := out.(map[string]any)
+	if !ok && out != nil {
+		return nil, fmt.Errorf("patch result is not an object: %T", out)
+	}
+	return outM, nil
+}
+
+func applyOp(doc any, op PatchOp) (any, error) {
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case OpAdd:
+		return pointerAdd(doc, tokens, cloneJSON(op.Value))
+	case OpRemove:
+		return pointerRemove(doc, tokens)
+	case OpReplace:
+		if _, err = pointerGet(doc, tokens); err != nil {
+			return nil, err
+		}
+		if doc, err = pointerRemove(doc, tokens); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, tokens, cloneJSON(op.Value))
+	case OpMove, OpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == OpCopy {
+			return pointerAdd(doc, tokens, cloneJSON(v))
+		}
+		if op.Path == op.From {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %v into its child", op.From)
+		}
+		if doc, err = pointerRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, tokens, v)
+	case OpTest:
+		v, err := pointerGet(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(v, op.Value) {
+			return nil, fmt.Errorf("test failed, got %v, want %v", v, op.Value)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// ApplyMergePatch apply RFC 7386 JSON Merge Patch to a copy of doc, doc is never modified.
+//
+// null in patch removes the key, nested objects are merged, everything else replaces.
+func ApplyMergePatch(doc map[string]any, patch map[string]any) map[string]any {
+	out, _ := cloneJSON(doc).(map[string]any)
+	if out == nil {
+		out = map[string]any{}
+	}
+	mergePatch(out, patch)
+	return out
+}
+
+func mergePatch(target map[string]any, patch map[string]any) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		if pm, ok := v.(map[string]any); ok {
+			tm, ok := target[k].(map[string]any)
+			if !ok {
+				tm = map[string]any{}
+			}
+			mergePatch(tm, pm)
+			target[k] = tm
+			continue
+		}
+		target[k] = cloneJSON(v)
+	}
+}
+
+// cloneJSON copy nested map[string]any and []any as a tree, shared sub-maps are copied separately unlike clonez.Deep so a patch never changes an alias
+func cloneJSON(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(vv))
+		for k, v1 := range vv {
+			out[k] = cloneJSON(v1)
+		}
+		return out
+	case []any:
+		out := make([]any, len(vv))
+		for i, v1 := range vv {
+			out[i] = cloneJSON(v1)
+		}
+		return out
+	}
+	return v
+}
+
+func jsonEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	//Number from patch or doc may be different go type (e.g. int from code, float64 from json)
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var na, nb any
+	if json.Unmarshal(ja, &na) != nil || json.Unmarshal(jb, &nb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid json pointer %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = unescapePointer(t)
+	}
+	return tokens, nil
+}
+
+func sliceIndex(token string, l int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return l, nil
+	}
+	//RFC 6901 index is "0" or digits without leading zero, no sign
+	if strings.Trim(token, "0123456789") != "" || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > l || (!allowEnd && i == l) {
+		return 0, fmt.Errorf("array index %v out of bounds", i)
+	}
+	return i, nil
+}
+
+func pointerGet(doc any, tokens []string) (any, error) {
+	out := doc
+	for _, t := range tokens {
+		switch v := out.(type) {
+		case map[string]any:
+			var ok bool
+			if out, ok = v[t]; !ok {
+				return nil, fmt.Errorf("key %q not found", t)
+			}
+		case []any:
+			i, err := sliceIndex(t, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			out = v[i]
+		default:
+			return nil, fmt.Errorf("cannot get %q from %T", t, out)
+		}
+	}
+	return out, nil
+}
+
+// pointerEdit walk to parent of last token and call edit on it. Return new doc because slice parent may be reallocated.
+func pointerEdit(doc any, tokens []string, edit func(parent any, key string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return edit(doc, tokens[0])
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		child, ok := v[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", tokens[0])
+		}
+		child, err := pointerEdit(child, tokens[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		v[tokens[0]] = child
+		return v, nil
+	case []any:
+		i, err := sliceIndex(tokens[0], len(v), false)
+		if err != nil {
+			return nil, err
+		}
+		child, err := pointerEdit(v[i], tokens[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = child
+		return v, nil
+	}
+	return nil, fmt.Errorf("cannot get %q from %T", tokens[0], doc)
+}
+
+func pointerAdd(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return pointerEdit(doc, tokens, func(parent any, key string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			v[key] = value
+			return v, nil
+		case []any:
+			i, err := sliceIndex(key, len(v), true)
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to %T", key, parent)
+	})
+}
+
+func pointerRemove(doc any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return pointerEdit(doc, tokens, func(parent any, key string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			if _, ok := v[key]; !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			delete(v, key)
+			return v, nil
+		case []any:
+			i, err := sliceIndex(key, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(v[:i], v[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q from %T", key, parent)
+	})
+}
@@ -0,0 +1,307 @@
+package mapz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		a map[string]any
+		b map[string]any
+	}
+	tests := []struct {
+		name string
+		args args
+		want Patch
+	}{
+		{
+			name: "equal",
+			args: args{
+				a: map[string]any{"x": map[string]any{"y": []any{1.0, "z"}}},
+				b: map[string]any{"x": map[string]any{"y": []any{1.0, "z"}}},
+			},
+			want: Patch{},
+		},
+		{
+			name: "add remove replace sorted",
+			args: args{
+				a: map[string]any{"c": 1.0, "a": "old", "d": nil},
+				b: map[string]any{"b": true, "a": "new", "d": nil},
+			},
+			want: Patch{
+				{Op: OpReplace, Path: "/a", Value: "new"},
+				{Op: OpAdd, Path: "/b", Value: true},
+				{Op: OpRemove, Path: "/c"},
+			},
+		},
+		{
+			name: "nested and escaped key",
+			args: args{
+				a: map[string]any{"x": map[string]any{"a/b": 1.0, "m~n": 2.0}},
+				b: map[string]any{"x": map[string]any{"a/b": 3.0, "m~n": 2.0}},
+			},
+			want: Patch{
+				{Op: OpReplace, Path: "/x/a~1b", Value: 3.0},
+			},
+		},
+		{
+			name: "slice grow",
+			args: args{
+				a: map[string]any{"x": []any{1.0}},
+				b: map[string]any{"x": []any{2.0, 3.0, 4.0}},
+			},
+			want: Patch{
+				{Op: OpReplace, Path: "/x/0", Value: 2.0},
+				{Op: OpAdd, Path: "/x/1", Value: 3.0},
+				{Op: OpAdd, Path: "/x/2", Value: 4.0},
+			},
+		},
+		{
+			name: "slice shrink removes from the end",
+			args: args{
+				a: map[string]any{"x": []any{1.0, 2.0, 3.0}},
+				b: map[string]any{"x": []any{1.0}},
+			},
+			want: Patch{
+				{Op: OpRemove, Path: "/x/2"},
+				{Op: OpRemove, Path: "/x/1"},
+			},
+		},
+		{
+			name: "type change",
+			args: args{
+				a: map[string]any{"x": map[string]any{"y": 1.0}},
+				b: map[string]any{"x": []any{1.0}},
+			},
+			want: Patch{
+				{Op: OpReplace, Path: "/x", Value: []any{1.0}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.args.a, tt.args.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+			applied, err := ApplyPatch(tt.args.a, got)
+			if err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(applied, tt.args.b) {
+				t.Errorf("ApplyPatch(Diff()) = %v, want %v", applied, tt.args.b)
+			}
+		})
+	}
+}
+
+func TestPatchJson(t *testing.T) {
+	t.Parallel()
+	p := Patch{
+		{Op: OpAdd, Path: "/a", Value: nil},
+		{Op: OpRemove, Path: "/b"},
+		{Op: OpMove, Path: "/c", From: "/d"},
+		{Op: OpCopy, Path: "/e", From: ""},
+	}
+	want := `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"},{"op":"move","path":"/c","from":"/d"},{"op":"copy","path":"/e","from":""}]`
+	bs, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(bs) != want {
+		t.Errorf("json.Marshal() = %v, want %v", string(bs), want)
+	}
+	got, err := ParsePatch(bs)
+	if err != nil {
+		t.Fatalf("ParsePatch() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("ParsePatch() = %v, want %v", got, p)
+	}
+	if _, err := ParsePatch([]byte(`{`)); err == nil {
+		t.Errorf("ParsePatch() expect error")
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+	doc := `{"foo":"bar","arr":[1,2,3],"obj":{"x":{"y":1}}}`
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "add key and insert array",
+			patch: `[{"op":"add","path":"/baz","value":"qux"},{"op":"add","path":"/arr/1","value":9},{"op":"add","path":"/arr/-","value":10}]`,
+			want:  `{"foo":"bar","baz":"qux","arr":[1,9,2,3,10],"obj":{"x":{"y":1}}}`,
+		},
+		{
+			name:  "remove",
+			patch: `[{"op":"remove","path":"/foo"},{"op":"remove","path":"/arr/0"}]`,
+			want:  `{"arr":[2,3],"obj":{"x":{"y":1}}}`,
+		},
+		{
+			name:  "replace",
+			patch: `[{"op":"replace","path":"/obj/x/y","value":[1]}]`,
+			want:  `{"foo":"bar","arr":[1,2,3],"obj":{"x":{"y":[1]}}}`,
+		},
+		{
+			name:  "move",
+			patch: `[{"op":"move","from":"/obj/x","path":"/x"}]`,
+			want:  `{"foo":"bar","arr":[1,2,3],"obj":{},"x":{"y":1}}`,
+		},
+		{
+			name:  "copy",
+			patch: `[{"op":"copy","from":"/arr","path":"/arr2"}]`,
+			want:  `{"foo":"bar","arr":[1,2,3],"arr2":[1,2,3],"obj":{"x":{"y":1}}}`,
+		},
+		{
+			name:  "test ok",
+			patch: `[{"op":"test","path":"/obj","value":{"x":{"y":1}}}]`,
+			want:  doc,
+		},
+		{
+			name:  "replace root",
+			patch: `[{"op":"replace","path":"","value":{"a":1}}]`,
+			want:  `{"a":1}`,
+		},
+		{
+			name:    "test fail is atomic",
+			patch:   `[{"op":"remove","path":"/foo"},{"op":"test","path":"/arr/0","value":2}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace missing",
+			patch:   `[{"op":"replace","path":"/nope","value":1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "index out of bounds",
+			patch:   `[{"op":"add","path":"/arr/5","value":1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "index with plus sign",
+			patch:   `[{"op":"replace","path":"/arr/+1","value":1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "index negative zero",
+			patch:   `[{"op":"remove","path":"/arr/-0"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "index leading zero",
+			patch:   `[{"op":"test","path":"/arr/01","value":2}]`,
+			wantErr: true,
+		},
+		{
+			name:    "index empty",
+			patch:   `[{"op":"add","path":"/arr/","value":1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "move into child",
+			patch:   `[{"op":"move","from":"/obj","path":"/obj/x/z"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown op",
+			patch:   `[{"op":"nope","path":"/foo"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid pointer",
+			patch:   `[{"op":"remove","path":"foo"}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := ToMap(doc)
+			p, err := ParsePatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParsePatch() error = %v", err)
+			}
+			got, err := ApplyPatch(in, p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(in, ToMap(doc)) {
+				t.Errorf("ApplyPatch() modified input %v", in)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want := ToMap(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("ApplyPatch() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "rfc 7386 example",
+			doc:   `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+			patch: `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`,
+			want:  `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`,
+		},
+		{
+			name:  "object replaces scalar",
+			doc:   `{"a":"b"}`,
+			patch: `{"a":{"c":null,"d":1}}`,
+			want:  `{"a":{"d":1}}`,
+		},
+		{
+			name:  "empty doc",
+			doc:   `null`,
+			patch: `{"a":1}`,
+			want:  `{"a":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := ToMap(tt.doc)
+			got := ApplyMergePatch(in, ToMap(tt.patch))
+			if want := ToMap(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("ApplyMergePatch() = %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(in, ToMap(tt.doc)) {
+				t.Errorf("ApplyMergePatch() modified input %v", in)
+			}
+		})
+	}
+}
+
+func TestApplyPatchAliased(t *testing.T) {
+	t.Parallel()
+	m := map[string]any{"x": 1.0}
+	doc := map[string]any{"a": m, "b": m}
+	got, err := ApplyPatch(doc, Patch{{Op: OpAdd, Path: "/a/y", Value: 2.0}})
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if want := ToMap(`{"a":{"x":1,"y":2},"b":{"x":1}}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyPatch() = %v, want %v", got, want)
+	}
+	merged := ApplyMergePatch(doc, map[string]any{"b": map[string]any{"x": nil}})
+	if want := ToMap(`{"a":{"x":1},"b":{}}`); !reflect.DeepEqual(merged, want) {
+		t.Errorf("ApplyMergePatch() = %v, want %v", merged, want)
+	}
+	if len(m) != 1 || m["x"] != 1.0 {
+		t.Errorf("patch modified aliased input %v", m)
+	}
+}
@@ -0,0 +1,187 @@
+package mapz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+const OptLines Option = "lines" //Stream every top-level value e.g. NDJSON / JSON Lines instead of the first one
+
+// ErrStop can be returned by Stream callback to stop without error.
+var ErrStop = errors.New("stop stream")
+
+// Match is one value found by Stream.
+type Match struct {
+	Doc   int      //Index of top-level value from 0, always 0 without OptLines
+	Path  []string //Actual keys e.g. items, 3, price
+	Value any
+}
+
+type streamKey struct {
+	raw   string
+	index int //Array index if raw is number, otherwise -1
+	reg   *regexp.Regexp
+}
+
+type streamer struct {
+	dec  *json.Decoder
+	keys []streamKey
+	doc  int
+	f    func(Match) error
+}
+
+// Stream evaluate conv.GetItems path against JSON from r token by token and call f for each match, so memory is bounded by matched values.
+//
+// Path syntax is the same as conv.GetItems (#, #v, #k, ^regexp, index), "" is the whole document. Different from GetItems:
+// matches are in document order (not sorted keys), and missing or null values are skipped as conv.OptOmitNoValue.
+//
+// Only the first top-level value is read unless OptLines to read all e.g. NDJSON. OptUseNumber decode number as json.Number.
+// Return error of f, except ErrStop that stop without error.
+func Stream(r io.Reader, path string, f func(Match) error, opts ...Option) error {
+	optsMap := make(map[Option]struct{}, len(opts))
+	for _, opt := range opts {
+		optsMap[opt] = struct{}{}
+	}
+	s := &streamer{dec: json.NewDecoder(r), f: f}
+	if _, ok := optsMap[OptUseNumber]; ok {
+		s.dec.UseNumber()
+	}
+	if path != "" {
+		for _, k := range strings.Split(path, ".") {
+			sk := streamKey{raw: k, index: -1}
+			if strings.HasPrefix(k, "^") {
+				reg, err := regexp.Compile(strings.ReplaceAll(k, conv.DotAlternative, "."))
+				if err != nil {
+					return fmt.Errorf("invalid path key %v: %w", k, err)
+				}
+				sk.reg = reg
+			} else if i, err := strconv.Atoi(k); err == nil && i >= 0 {
+				sk.index = i
+			}
+			s.keys = append(s.keys, sk)
+		}
+	}
+	_, lines := optsMap[OptLines]
+	for {
+		if lines && !s.dec.More() {
+			return nil
+		}
+		err := s.walk(0, nil)
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("fail stream json value %v: %w", s.doc, err)
+		}
+		if !lines {
+			return nil
+		}
+		s.doc++
+	}
+}
+
+// StreamChan is Stream that send matches to channel, both channels are closed at the end.
+// Error channel receive at most one error. Cancel ctx to stop early, ctx.Err() is sent.
+func StreamChan(ctx context.Context, r io.Reader, path string, opts ...Option) (<-chan Match, <-chan error) {
+	out := make(chan Match)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		err := Stream(r, path, func(m Match) error {
+			select {
+			case out <- m:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, opts...)
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return out, errc
+}
+
+// walk the next value with keys[i:], path is actual keys of the value
+func (s *streamer) walk(i int, path []string) error {
+	if i == len(s.keys) {
+		var v any
+		if err := s.dec.Decode(&v); err != nil {
+			return err
+		}
+		if v == nil {
+			return nil
+		}
+		return s.f(Match{Doc: s.doc, Path: append([]string{}, path...), Value: v})
+	}
+	t, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := t.(json.Delim)
+	if !ok { //Scalar has nothing under it
+		return nil
+	}
+	key := s.keys[i]
+	for idx := 0; s.dec.More(); idx++ {
+		var k string
+		var kv any = idx
+		if delim == '{' {
+			if t, err = s.dec.Token(); err != nil {
+				return err
+			}
+			k, kv = t.(string), t
+		} else {
+			k = strconv.Itoa(idx)
+		}
+		switch {
+		case key.raw == "#k":
+			if i+1 == len(s.keys) {
+				if err := s.f(Match{Doc: s.doc, Path: append(append([]string{}, path...), k), Value: kv}); err != nil {
+					return err
+				}
+			}
+			err = s.skip()
+		case key.raw == "#" || key.raw == "#v" ||
+			(delim == '{' && (key.reg != nil && key.reg.MatchString(k) || key.reg == nil && key.raw == k)) ||
+			(delim == '[' && key.index == idx):
+			err = s.walk(i+1, append(path, k))
+		default:
+			err = s.skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = s.dec.Token() //Closing delim
+	return err
+}
+
+// skip the next value without decoding
+func (s *streamer) skip() error {
+	depth := 0
+	for {
+		t, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
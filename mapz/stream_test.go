@@ -0,0 +1,150 @@
+package mapz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+const streamDoc = `{
+	"id": 1,
+	"items": [
+		{"sku": "A", "price": 1.5, "tags": ["x"]},
+		{"sku": "B", "price": null},
+		{"sku": "C", "price": 3, "meta": {"a.b": 1}}
+	],
+	"value_1": "v1", "value_2": "v2", "other": {"nested": [1, 2]}
+}`
+
+func collectStream(t *testing.T, in, path string, opts ...Option) ([]Match, error) {
+	t.Helper()
+	var out []Match
+	err := Stream(strings.NewReader(in), path, func(m Match) error {
+		out = append(out, m)
+		return nil
+	}, opts...)
+	return out, err
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path      string
+		want      []any
+		wantPaths []string
+	}{
+		{path: "items.#.price", want: []any{1.5, 3.0}, wantPaths: []string{"items.0.price", "items.2.price"}},
+		{path: "items.1.sku", want: []any{"B"}, wantPaths: []string{"items.1.sku"}},
+		{path: "items.#k", want: []any{0, 1, 2}, wantPaths: []string{"items.0", "items.1", "items.2"}},
+		{path: "#k", want: []any{"id", "items", "value_1", "value_2", "other"}},
+		{path: "^value_", want: []any{"v1", "v2"}, wantPaths: []string{"value_1", "value_2"}},
+		{path: "items.#.meta.^a" + conv.DotAlternative + "b", want: []any{1.0}},
+		{path: "items.0.tags", want: []any{[]any{"x"}}},
+		{path: "other", want: []any{map[string]any{"nested": []any{1.0, 2.0}}}},
+		{path: "id.x", want: nil},
+		{path: "missing", want: nil},
+		{path: "items.9", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			matches, err := collectStream(t, streamDoc, tt.path)
+			if err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+			var got []any
+			var paths []string
+			for _, m := range matches {
+				got = append(got, m.Value)
+				paths = append(paths, strings.Join(m.Path, "."))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stream() = %#v, want %#v", got, tt.want)
+			}
+			if tt.wantPaths != nil && !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("Stream() paths = %v, want %v", paths, tt.wantPaths)
+			}
+			//Same values as GetItems, GetItems order map keys so compare only array path
+			if !strings.Contains(tt.path, "^") && !strings.HasPrefix(tt.path, "#") {
+				if want := conv.GetItems(ToMap(streamDoc), tt.path, conv.OptOmitNoValue); len(want) != len(got) {
+					t.Errorf("Stream() = %v, GetItems() = %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamOptions(t *testing.T) {
+	t.Parallel()
+	matches, err := collectStream(t, "", "")
+	if err == nil {
+		t.Errorf("Stream() expect error for empty input, got %v", matches)
+	}
+	if matches, err = collectStream(t, `{"a":1} {"a":2}`, "a", OptUseNumber); err != nil || len(matches) != 1 || matches[0].Value != json.Number("1") {
+		t.Errorf("Stream() first value = %v, %v", matches, err)
+	}
+	ndjson := "{\"a\":1}\n\n{\"b\":1}\n{\"a\":[3]}\n"
+	matches, err = collectStream(t, ndjson, "a", OptLines)
+	if err != nil || len(matches) != 2 || matches[0].Doc != 0 || matches[1].Doc != 2 || !reflect.DeepEqual(matches[1].Value, []any{3.0}) {
+		t.Errorf("Stream() lines = %v, %v", matches, err)
+	}
+	if _, err = collectStream(t, "{\"a\":1}\n{\"a\":", "a", OptLines); err == nil || !strings.Contains(err.Error(), "value 1") {
+		t.Errorf("Stream() expect error for truncated line, got %v", err)
+	}
+	if _, err = collectStream(t, `{"a":[1,}`, "a.#"); err == nil {
+		t.Errorf("Stream() expect error for invalid json")
+	}
+	if _, err = collectStream(t, `{}`, "^("); err == nil {
+		t.Errorf("Stream() expect error for invalid regexp")
+	}
+	n := 0
+	err = Stream(strings.NewReader(`[1,2,3]`), "#", func(m Match) error {
+		if n++; n == 2 {
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil || n != 2 {
+		t.Errorf("Stream() ErrStop = %v, calls %v", err, n)
+	}
+	errFail := errors.New("fail")
+	if err = Stream(strings.NewReader(`[1]`), "#", func(m Match) error { return errFail }); !errors.Is(err, errFail) {
+		t.Errorf("Stream() error = %v, want %v", err, errFail)
+	}
+}
+
+func TestStreamChan(t *testing.T) {
+	t.Parallel()
+	out, errc := StreamChan(context.Background(), strings.NewReader(streamDoc), "items.#.sku")
+	var got []any
+	for m := range out {
+		got = append(got, m.Value)
+	}
+	if err := <-errc; err != nil || !reflect.DeepEqual(got, []any{"A", "B", "C"}) {
+		t.Errorf("StreamChan() = %v, %v", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, errc = StreamChan(ctx, strings.NewReader(streamDoc), "items.#.sku")
+	<-out
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) { //Not reading out so the sender must see ctx.Done
+		t.Errorf("StreamChan() canceled error = %v", err)
+	}
+	if _, ok := <-out; ok {
+		t.Errorf("StreamChan() out is not closed")
+	}
+}
+
+func BenchmarkStream(b *testing.B) {
+	in := `{"items":[` + strings.Repeat(`{"sku":"A","price":1.5,"tags":["x","y"]},`, 10000) + `{}]}`
+	b.SetBytes(int64(len(in)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Stream(strings.NewReader(in), "items.#.price", func(m Match) error { return nil })
+	}
+}
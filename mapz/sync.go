@@ -0,0 +1,323 @@
+package mapz
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"reflect"
+	"sync"
+)
+
+// Sync is map guarded by sync.RWMutex, safe for concurrent use. Zero value is ready to use, must not be copied after first use.
+type Sync[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+func NewSync[K comparable, V any]() *Sync[K, V] {
+	return &Sync[K, V]{m: make(map[K]V)}
+}
+
+func (s *Sync[K, V]) Load(key K) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+func (s *Sync[K, V]) Store(key K, val V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[K]V)
+	}
+	s.m[key] = val
+}
+
+// LoadOrStore return existing value if exists (loaded true), otherwise store and return val.
+func (s *Sync[K, V]) LoadOrStore(key K, val V) (actual V, loaded bool) {
+	if v, ok := s.Load(key); ok { //Fast path with read lock
+		return v, true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	if s.m == nil {
+		s.m = make(map[K]V)
+	}
+	s.m[key] = val
+	return val, false
+}
+
+// LoadAndDelete delete key and return its previous value.
+func (s *Sync[K, V]) LoadAndDelete(key K) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	delete(s.m, key)
+	return v, ok
+}
+
+func (s *Sync[K, V]) Delete(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// Compute set key to value returned by f atomically. old and loaded are current value, f return keep false to delete key.
+//
+// f is called under lock, it must not access s.
+func (s *Sync[K, V]) Compute(key K, f func(old V, loaded bool) (val V, keep bool)) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, loaded := s.m[key]
+	val, keep := f(old, loaded)
+	if !keep {
+		delete(s.m, key)
+		var zero V
+		return zero, false
+	}
+	if s.m == nil {
+		s.m = make(map[K]V)
+	}
+	s.m[key] = val
+	return val, true
+}
+
+// Range call f for each entry of a snapshot until f return false. f may access s.
+func (s *Sync[K, V]) Range(f func(key K, val V) bool) {
+	for k, v := range s.Snapshot() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+func (s *Sync[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.m)
+}
+
+func (s *Sync[K, V]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m = make(map[K]V)
+}
+
+// Snapshot return copy as plain map, use with other mapz helpers e.g. Keys(s.Snapshot(), Less[string]).
+func (s *Sync[K, V]) Snapshot() map[K]V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[K]V, len(s.m))
+	for k, v := range s.m {
+		out[k] = v
+	}
+	return out
+}
+
+// Sharded is map split into many Sync shards by key hash to reduce lock contention. Create by NewSharded.
+type Sharded[K comparable, V any] struct {
+	shards []*Sync[K, V]
+	mask   uint64
+	seed   maphash.Seed
+	hash   func(key K) uint64
+}
+
+// NewSharded create Sharded with shards rounded up to power of 2 (<1 is 16).
+//
+// hash is optional, default support string, integer, float and bool types; other key types are hashed by reflection which is slower.
+// Default hash treat 0 and -0 as the same key like Go map, NaN keys all go to the same shard but never equal so can't be loaded.
+func NewSharded[K comparable, V any](shards int, hash ...func(key K) uint64) *Sharded[K, V] {
+	if shards < 1 {
+		shards = 16
+	}
+	n := 1 << bits.Len(uint(shards-1))
+	s := &Sharded[K, V]{
+		shards: make([]*Sync[K, V], n),
+		mask:   uint64(n - 1),
+		seed:   maphash.MakeSeed(),
+	}
+	for i := range s.shards {
+		s.shards[i] = NewSync[K, V]()
+	}
+	if len(hash) > 0 && hash[0] != nil {
+		s.hash = hash[0]
+	} else {
+		s.hash = s.defaultHash
+	}
+	return s
+}
+
+func (s *Sharded[K, V]) defaultHash(key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(s.seed, k)
+	case int:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uint32:
+		return mix64(uint64(k))
+	case float64:
+		return mix64(floatBits(k))
+	case float32:
+		return mix64(floatBits(float64(k)))
+	case bool:
+		if k {
+			return 1
+		}
+		return 0
+	}
+	var h maphash.Hash
+	h.SetSeed(s.seed)
+	hashValue(&h, reflect.ValueOf(&key).Elem())
+	return h.Sum64()
+}
+
+// hashValue write v to h so that == values write the same bytes
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		writeUint64(h, uint64(v.Len()))
+		h.WriteString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint64(h, floatBits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeUint64(h, floatBits(real(c)))
+		writeUint64(h, floatBits(imag(c)))
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		h.WriteString(v.Elem().Type().String())
+		hashValue(h, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashValue(h, v.Field(i))
+		}
+	}
+}
+
+func writeUint64(h *maphash.Hash, x uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	h.Write(b[:])
+}
+
+// floatBits return bits of f with -0 as 0 and all NaN as the same
+func floatBits(f float64) uint64 {
+	switch {
+	case f == 0:
+		return 0
+	case f != f:
+		return 0x7ff8000000000001
+	}
+	return math.Float64bits(f)
+}
+
+// mix64 spread sequential integer across shards (splitmix64 finalizer)
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func (s *Sharded[K, V]) shard(key K) *Sync[K, V] {
+	return s.shards[s.hash(key)&s.mask]
+}
+
+func (s *Sharded[K, V]) Load(key K) (V, bool) {
+	return s.shard(key).Load(key)
+}
+
+func (s *Sharded[K, V]) Store(key K, val V) {
+	s.shard(key).Store(key, val)
+}
+
+func (s *Sharded[K, V]) LoadOrStore(key K, val V) (actual V, loaded bool) {
+	return s.shard(key).LoadOrStore(key, val)
+}
+
+func (s *Sharded[K, V]) LoadAndDelete(key K) (V, bool) {
+	return s.shard(key).LoadAndDelete(key)
+}
+
+func (s *Sharded[K, V]) Delete(key K) {
+	s.shard(key).Delete(key)
+}
+
+// Compute see Sync.Compute, only the shard of key is locked.
+func (s *Sharded[K, V]) Compute(key K, f func(old V, loaded bool) (val V, keep bool)) (V, bool) {
+	return s.shard(key).Compute(key, f)
+}
+
+// Range call f for each entry shard by shard until f return false. Each shard is a snapshot, not the whole map.
+func (s *Sharded[K, V]) Range(f func(key K, val V) bool) {
+	for _, sh := range s.shards {
+		stop := false
+		sh.Range(func(k K, v V) bool {
+			stop = !f(k, v)
+			return !stop
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+func (s *Sharded[K, V]) Len() int {
+	n := 0
+	for _, sh := range s.shards {
+		n += sh.Len()
+	}
+	return n
+}
+
+func (s *Sharded[K, V]) Clear() {
+	for _, sh := range s.shards {
+		sh.Clear()
+	}
+}
+
+// Snapshot return copy as plain map, shards are copied one by one.
+func (s *Sharded[K, V]) Snapshot() map[K]V {
+	out := make(map[K]V)
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		for k, v := range sh.m {
+			out[k] = v
+		}
+		sh.mu.RUnlock()
+	}
+	return out
+}
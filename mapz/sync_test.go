@@ -0,0 +1,264 @@
+package mapz
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// syncMap is common methods of Sync and Sharded to run the same tests
+type syncMap[K comparable, V any] interface {
+	Load(key K) (V, bool)
+	Store(key K, val V)
+	LoadOrStore(key K, val V) (V, bool)
+	LoadAndDelete(key K) (V, bool)
+	Delete(key K)
+	Compute(key K, f func(old V, loaded bool) (V, bool)) (V, bool)
+	Range(f func(key K, val V) bool)
+	Len() int
+	Clear()
+	Snapshot() map[K]V
+}
+
+func syncMaps() map[string]syncMap[string, int] {
+	return map[string]syncMap[string, int]{
+		"Sync zero value": &Sync[string, int]{},
+		"Sync":            NewSync[string, int](),
+		"Sharded":         NewSharded[string, int](4),
+		"Sharded 1 shard": NewSharded[string, int](1),
+		"Sharded custom hash": NewSharded[string, int](3, func(key string) uint64 {
+			return uint64(len(key))
+		}),
+	}
+}
+
+func TestSyncMap(t *testing.T) {
+	t.Parallel()
+	for name, m := range syncMaps() {
+		m := m
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if v, ok := m.Load("a"); ok || v != 0 {
+				t.Errorf("Load() empty = %v, %v", v, ok)
+			}
+			m.Store("a", 1)
+			if v, loaded := m.LoadOrStore("a", 2); !loaded || v != 1 {
+				t.Errorf("LoadOrStore() existing = %v, %v", v, loaded)
+			}
+			if v, loaded := m.LoadOrStore("b", 2); loaded || v != 2 {
+				t.Errorf("LoadOrStore() new = %v, %v", v, loaded)
+			}
+			if v, ok := m.Compute("b", func(old int, loaded bool) (int, bool) { return old + 10, true }); !ok || v != 12 {
+				t.Errorf("Compute() = %v, %v", v, ok)
+			}
+			if v, ok := m.Compute("c", func(old int, loaded bool) (int, bool) { return 3, !loaded }); !ok || v != 3 {
+				t.Errorf("Compute() new = %v, %v", v, ok)
+			}
+			if want := map[string]int{"a": 1, "b": 12, "c": 3}; !reflect.DeepEqual(m.Snapshot(), want) {
+				t.Errorf("Snapshot() = %v, want %v", m.Snapshot(), want)
+			}
+			if got, want := Keys(m.Snapshot(), Less[string]), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Keys(Snapshot()) = %v, want %v", got, want)
+			}
+			if _, ok := m.Compute("c", func(old int, loaded bool) (int, bool) { return 0, false }); ok || m.Len() != 2 {
+				t.Errorf("Compute() delete fail, len %v", m.Len())
+			}
+			if v, ok := m.LoadAndDelete("a"); !ok || v != 1 {
+				t.Errorf("LoadAndDelete() = %v, %v", v, ok)
+			}
+			if _, ok := m.LoadAndDelete("a"); ok {
+				t.Errorf("LoadAndDelete() deleted key found")
+			}
+			m.Delete("b")
+			m.Store("x", 1)
+			m.Store("y", 2)
+			n := 0
+			m.Range(func(_ string, _ int) bool {
+				n++
+				return false
+			})
+			if n != 1 {
+				t.Errorf("Range() stop fail, called %v", n)
+			}
+			m.Clear()
+			if m.Len() != 0 {
+				t.Errorf("Clear() fail, len %v", m.Len())
+			}
+		})
+	}
+}
+
+func TestSyncMapConcurrent(t *testing.T) {
+	t.Parallel()
+	const workers, n = 8, 500
+	for name, m := range syncMaps() {
+		m := m
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < n; i++ {
+						k := strconv.Itoa(i)
+						m.Compute("counter", func(old int, _ bool) (int, bool) { return old + 1, true })
+						m.LoadOrStore(k, i)
+						m.Load(k)
+						m.Store("w"+strconv.Itoa(w), i)
+						if i%50 == 0 {
+							m.Range(func(_ string, _ int) bool { return true })
+							m.Snapshot()
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+			if v, _ := m.Load("counter"); v != workers*n {
+				t.Errorf("counter = %v, want %v", v, workers*n)
+			}
+			if m.Len() != n+workers+1 {
+				t.Errorf("Len() = %v, want %v", m.Len(), n+workers+1)
+			}
+		})
+	}
+}
+
+func TestShardedKeyTypes(t *testing.T) {
+	t.Parallel()
+	type key struct {
+		a int
+		b string
+	}
+	s := NewSharded[key, int](0)
+	if len(s.shards) != 16 {
+		t.Errorf("default shards = %v, want 16", len(s.shards))
+	}
+	s.Store(key{1, "x"}, 1)
+	if v, ok := s.Load(key{1, "x"}); !ok || v != 1 {
+		t.Errorf("Load() struct key = %v, %v", v, ok)
+	}
+	i := NewSharded[int, int](5)
+	if len(i.shards) != 8 {
+		t.Errorf("shards = %v, want 8", len(i.shards))
+	}
+	for k := 0; k < 100; k++ {
+		i.Store(k, k)
+	}
+	if i.Len() != 100 {
+		t.Errorf("Len() = %v, want 100", i.Len())
+	}
+}
+
+func TestShardedFloatKeys(t *testing.T) {
+	t.Parallel()
+	negZero := math.Copysign(0, -1)
+	f := NewSharded[float64, int](64)
+	f.Store(negZero, 1)
+	if v, ok := f.Load(0); !ok || v != 1 {
+		t.Errorf("Load(0) after Store(-0) = %v, %v", v, ok)
+	}
+	f.Store(math.NaN(), 2)
+	f.Store(math.NaN(), 3)
+	if _, ok := f.Load(math.NaN()); ok || f.Len() != 3 {
+		t.Errorf("NaN key should never be loaded, Len() = %v", f.Len())
+	}
+
+	type point struct {
+		X, Y float32
+		Tag  any
+	}
+	p := NewSharded[point, int](64)
+	p.Store(point{X: float32(negZero), Tag: negZero}, 1)
+	if v, ok := p.Load(point{Tag: 0.0}); !ok || v != 1 {
+		t.Errorf("Load() struct with 0 after -0 = %v, %v", v, ok)
+	}
+	type celsius float64
+	c := NewSharded[celsius, int](64)
+	c.Store(celsius(negZero), 1)
+	if v, ok := c.Load(0); !ok || v != 1 {
+		t.Errorf("Load() named float 0 after -0 = %v, %v", v, ok)
+	}
+}
+
+const benchKeys = 1024
+
+func benchKeyList() []string {
+	keys := make([]string, benchKeys)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func BenchmarkSyncMapReadMostly(b *testing.B) {
+	keys := benchKeyList()
+	var m sync.Map
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%100 == 0 {
+				m.Store(keys[i%benchKeys], i)
+			} else {
+				m.Load(keys[i%benchKeys])
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncReadMostly(b *testing.B) {
+	benchSyncMap(b, NewSync[string, int](), 100)
+}
+
+func BenchmarkShardedReadMostly(b *testing.B) {
+	benchSyncMap(b, NewSharded[string, int](32), 100)
+}
+
+func BenchmarkSyncMapWriteHeavy(b *testing.B) {
+	keys := benchKeyList()
+	var m sync.Map
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				m.Store(keys[i%benchKeys], i)
+			} else {
+				m.Load(keys[i%benchKeys])
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncWriteHeavy(b *testing.B) {
+	benchSyncMap(b, NewSync[string, int](), 2)
+}
+
+func BenchmarkShardedWriteHeavy(b *testing.B) {
+	benchSyncMap(b, NewSharded[string, int](32), 2)
+}
+
+func benchSyncMap(b *testing.B, m syncMap[string, int], writeEvery int) {
+	keys := benchKeyList()
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%writeEvery == 0 {
+				m.Store(keys[i%benchKeys], i)
+			} else {
+				m.Load(keys[i%benchKeys])
+			}
+			i++
+		}
+	})
+}
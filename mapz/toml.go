@@ -0,0 +1,446 @@
+package mapz
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+var regTomlDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+type tomlParser struct {
+	s       string
+	pos     int
+	root    map[string]any
+	cur     map[string]any
+	defined map[string]struct{}   //Table header paths already defined, to report duplicate
+	static  map[tomlSlot]struct{} //Inline tables and arrays, they can't be extended by table header or dotted key
+}
+
+// tomlSlot is key of table
+type tomlSlot struct {
+	table uintptr
+	key   string
+}
+
+func slotOf(t map[string]any, key string) tomlSlot {
+	return tomlSlot{table: reflect.ValueOf(t).Pointer(), key: key}
+}
+
+func decodeToml(s string) (map[string]any, error) {
+	p := &tomlParser{s: strings.ReplaceAll(s, "\r\n", "\n"), root: map[string]any{}, defined: map[string]struct{}{}, static: map[tomlSlot]struct{}{}}
+	p.cur = p.root
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.s) {
+			return p.root, nil
+		}
+		var err error
+		if p.s[p.pos] == '[' {
+			err = p.parseTable()
+		} else {
+			err = p.parseKeyValue(p.cur)
+		}
+		if err == nil {
+			err = p.endOfLine()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("toml line %d: %w", p.line(), err)
+		}
+	}
+}
+
+func (p *tomlParser) line() int {
+	return strings.Count(p.s[:p.pos], "\n") + 1
+}
+
+func (p *tomlParser) ws() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipBlank skip whitespace, newline and comment
+func (p *tomlParser) skipBlank() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n':
+			p.pos++
+		case '#':
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.ws()
+	if p.pos < len(p.s) && p.s[p.pos] == '#' {
+		for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+			p.pos++
+		}
+	}
+	if p.pos < len(p.s) && p.s[p.pos] != '\n' {
+		return fmt.Errorf("expect end of line, got %q", p.s[p.pos])
+	}
+	return nil
+}
+
+func (p *tomlParser) parseTable() error {
+	isArray := strings.HasPrefix(p.s[p.pos:], "[[")
+	p.pos += conv.Ternary(isArray, 2, 1)
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	closing := conv.Ternary(isArray, "]]", "]")
+	if !strings.HasPrefix(p.s[p.pos:], closing) {
+		return fmt.Errorf("expect %v", closing)
+	}
+	p.pos += len(closing)
+	parent, err := p.walk(p.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, ok := p.static[slotOf(parent, last)]; ok {
+		return fmt.Errorf("inline value %q can't be extended", strings.Join(keys, "."))
+	}
+	path := strings.Join(keys, "\x00")
+	if isArray {
+		arr, ok := parent[last].([]any)
+		if _, exists := parent[last]; exists && !ok {
+			return fmt.Errorf("key %q is not array of tables", strings.Join(keys, "."))
+		}
+		p.cur = map[string]any{}
+		parent[last] = append(arr, p.cur)
+		p.clearDefined(path) //Keys of previous element can be defined again
+		return nil
+	}
+	if _, exists := p.defined[path]; exists {
+		return fmt.Errorf("table %q defined twice", strings.Join(keys, "."))
+	}
+	p.defined[path] = struct{}{}
+	t, ok := parent[last].(map[string]any)
+	if !ok {
+		if _, exists := parent[last]; exists {
+			return fmt.Errorf("key %q is not table", strings.Join(keys, "."))
+		}
+		t = map[string]any{}
+		parent[last] = t
+	}
+	p.cur = t
+	return nil
+}
+
+func (p *tomlParser) clearDefined(prefix string) {
+	for k := range p.defined {
+		if strings.HasPrefix(k, prefix+"\x00") {
+			delete(p.defined, k)
+		}
+	}
+}
+
+// walk go down keys creating table as needed, array of tables use its last element
+func (p *tomlParser) walk(t map[string]any, keys []string) (map[string]any, error) {
+	for i, k := range keys {
+		if _, ok := p.static[slotOf(t, k)]; ok {
+			return nil, fmt.Errorf("inline value %q can't be extended", strings.Join(keys[:i+1], "."))
+		}
+		switch v := t[k].(type) {
+		case nil:
+			if _, exists := t[k]; exists {
+				return nil, fmt.Errorf("key %q is not table", strings.Join(keys[:i+1], "."))
+			}
+			next := map[string]any{}
+			t[k] = next
+			t = next
+		case map[string]any:
+			t = v
+		case []any:
+			if len(v) == 0 {
+				return nil, fmt.Errorf("key %q is not table", strings.Join(keys[:i+1], "."))
+			}
+			last, ok := v[len(v)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("key %q is not table", strings.Join(keys[:i+1], "."))
+			}
+			t = last
+		default:
+			return nil, fmt.Errorf("key %q is not table", strings.Join(keys[:i+1], "."))
+		}
+	}
+	return t, nil
+}
+
+func (p *tomlParser) parseKey() ([]string, error) {
+	keys := []string{}
+	for {
+		p.ws()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("expect key")
+		}
+		var k string
+		var err error
+		switch p.s[p.pos] {
+		case '"', '\'':
+			if k, err = p.parseString(); err != nil {
+				return nil, err
+			}
+		default:
+			start := p.pos
+			for p.pos < len(p.s) && isTomlBareKey(p.s[p.pos]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, fmt.Errorf("invalid key character %q", p.s[p.pos])
+			}
+			k = p.s[start:p.pos]
+		}
+		keys = append(keys, k)
+		p.ws()
+		if p.pos >= len(p.s) || p.s[p.pos] != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isTomlBareKey(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseKeyValue parse key = value into t
+func (p *tomlParser) parseKeyValue(t map[string]any) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.ws(); p.pos >= len(p.s) || p.s[p.pos] != '=' {
+		return fmt.Errorf("expect =")
+	}
+	p.pos++
+	v, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := p.walk(t, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return fmt.Errorf("duplicate key %q", strings.Join(keys, "."))
+	}
+	parent[last] = v
+	switch v.(type) {
+	case map[string]any, []any:
+		p.static[slotOf(parent, last)] = struct{}{}
+	}
+	return nil
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	p.ws()
+	if p.pos >= len(p.s) {
+		return nil, fmt.Errorf("expect value")
+	}
+	switch c := p.s[p.pos]; {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	case strings.HasPrefix(p.s[p.pos:], "true"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(p.s[p.pos:], "false"):
+		p.pos += 5
+		return false, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && (isTomlBareKey(p.s[p.pos]) || strings.IndexByte("+.:", p.s[p.pos]) >= 0) {
+		p.pos++
+	}
+	tok := p.s[start:p.pos]
+	if regTomlDate.MatchString(tok) && p.pos+1 < len(p.s) && p.s[p.pos] == ' ' && p.s[p.pos+1] >= '0' && p.s[p.pos+1] <= '9' {
+		p.pos++ //Date time separated by space
+		for p.pos < len(p.s) && (isTomlBareKey(p.s[p.pos]) || strings.IndexByte("+.:", p.s[p.pos]) >= 0) {
+			p.pos++
+		}
+		tok = p.s[start:p.pos]
+	}
+	if tok == "" {
+		return nil, fmt.Errorf("invalid value %q", p.s[p.pos])
+	}
+	if strings.Contains(tok, ":") || regTomlDate.MatchString(tok) || len(tok) > 10 && regTomlDate.MatchString(tok[:10]) {
+		return tok, nil //Date time is kept as string
+	}
+	if strings.Contains(tok, "__") || strings.HasPrefix(tok, "_") || strings.HasSuffix(tok, "_") {
+		return nil, fmt.Errorf("invalid number %q", tok)
+	}
+	if u := strings.TrimLeft(tok, "+-"); len(u) > 1 && u[0] == '0' && (u[1] >= '0' && u[1] <= '9' || u[1] == '_') {
+		return nil, fmt.Errorf("invalid number %q: leading zero", tok)
+	}
+	if f, ok := parseNumber(strings.ReplaceAll(tok, "_", "")); ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", tok)
+}
+
+func (p *tomlParser) parseArray() (any, error) {
+	p.pos++
+	out := make([]any, 0)
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		if p.s[p.pos] == ']' {
+			p.pos++
+			return out, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		p.skipBlank()
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+		} else if p.pos >= len(p.s) || p.s[p.pos] != ']' {
+			return nil, fmt.Errorf("expect , or ] in array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (any, error) {
+	p.pos++
+	out := make(map[string]any)
+	for first := true; ; first = false {
+		p.ws()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("unterminated inline table")
+		}
+		if p.s[p.pos] == '}' && first {
+			p.pos++
+			return out, nil
+		}
+		if err := p.parseKeyValue(out); err != nil {
+			return nil, err
+		}
+		p.ws()
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '}' {
+			p.pos++
+			return out, nil
+		}
+		return nil, fmt.Errorf("expect , or } in inline table")
+	}
+}
+
+func (p *tomlParser) parseString() (string, error) {
+	rest := p.s[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`), strings.HasPrefix(rest, "'''"):
+		delim := rest[:3]
+		body := rest[3:]
+		end := strings.Index(body, delim)
+		if delim == `"""` {
+			end = -1
+			for i := 0; i+3 <= len(body); i++ {
+				if body[i] == '\\' {
+					i++
+				} else if strings.HasPrefix(body[i:], delim) {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			return "", fmt.Errorf("unterminated multi-line string")
+		}
+		for end+3 < len(body) && body[end+3] == delim[0] { //Up to 2 quotes are allowed before closing
+			end++
+		}
+		p.pos += 3 + end + 3
+		s := strings.TrimPrefix(body[:end], "\n") //Newline after opening delimiter is trimmed
+		if delim == "'''" {
+			return s, nil
+		}
+		return unescapeToml(s, true)
+	case rest[0] == '\'':
+		end := strings.IndexAny(rest[1:], "'\n")
+		if end < 0 || rest[1+end] != '\'' {
+			return "", fmt.Errorf("unterminated literal string")
+		}
+		p.pos += end + 2
+		return rest[1 : end+1], nil
+	}
+	end := closingQuote(rest, '"')
+	if end < 0 || strings.Contains(rest[:end], "\n") {
+		return "", fmt.Errorf("unterminated string")
+	}
+	p.pos += end + 1
+	return unescapeToml(rest[1:end], false)
+}
+
+func unescapeToml(s string, multiline bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("invalid escape at end of string")
+		}
+		switch c := s[i]; c {
+		case 'b':
+			b.WriteByte('\b')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'f':
+			b.WriteByte('\f')
+		case 'r':
+			b.WriteByte('\r')
+		case 'e':
+			b.WriteByte(0x1b)
+		case '"', '\\':
+			b.WriteByte(c)
+		case 'u', 'U':
+			n := conv.Ternary(c == 'u', 4, 8)
+			if i+1+n > len(s) {
+				return "", fmt.Errorf("invalid unicode escape")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i+1:i+1+n])
+			}
+			b.WriteRune(rune(r))
+			i += n
+		default:
+			if rest := strings.TrimLeft(s[i:], " \t"); multiline && strings.HasPrefix(rest, "\n") {
+				//Line ending backslash trim newline and following whitespace
+				i = len(s) - len(strings.TrimLeft(rest, " \t\n")) - 1
+				continue
+			}
+			return "", fmt.Errorf("invalid escape \\%c", c)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,465 @@
+package mapz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string //Without indent, comment is not removed
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	i     int
+}
+
+func decodeYaml(s string) (map[string]any, error) {
+	p := &yamlParser{}
+	started := false
+	for i, raw := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		text := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(text, "\t") && strings.TrimSpace(text) != "" {
+			return nil, fmt.Errorf("yaml line %d: tab is not allowed for indentation", i+1)
+		}
+		if len(raw)-len(text) == 0 {
+			switch strings.TrimRight(text, " ") {
+			case "---":
+				if started {
+					return nil, fmt.Errorf("yaml line %d: multi-document is not supported", i+1)
+				}
+				started = true
+				continue
+			case "...":
+				return p.parseDocument()
+			}
+		}
+		if !isYamlBlank(text) {
+			started = true
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(raw) - len(text), text: strings.TrimRight(text, " \t")})
+	}
+	return p.parseDocument()
+}
+
+func (p *yamlParser) parseDocument() (map[string]any, error) {
+	p.skipBlank()
+	if p.i >= len(p.lines) {
+		return map[string]any{}, nil
+	}
+	v, err := p.parseBlock(p.lines[p.i].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipBlank(); p.i < len(p.lines) {
+		return nil, fmt.Errorf("yaml line %d: bad indentation", p.lines[p.i].num)
+	}
+	out, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("fail cast to map, yaml top-level value is %T", v)
+	}
+	return out, nil
+}
+
+func isYamlBlank(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || strings.HasPrefix(text, "#")
+}
+
+func isYamlSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) skipBlank() {
+	for p.i < len(p.lines) && isYamlBlank(p.lines[p.i].text) {
+		p.i++
+	}
+}
+
+// parseBlock parse node starting at current line which is not blank
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	l := p.lines[p.i]
+	if isYamlSeqItem(l.text) {
+		return p.parseSeq(indent)
+	}
+	if _, _, ok, _ := splitYamlKey(l.text); ok {
+		return p.parseMap(indent)
+	}
+	p.i++
+	return p.parseValue(l.text, l.num, indent-1)
+}
+
+// parseChild parse node more indented than parent, nil if there is none
+func (p *yamlParser) parseChild(parent int) (any, error) {
+	p.skipBlank()
+	if p.i < len(p.lines) && p.lines[p.i].indent > parent {
+		return p.parseBlock(p.lines[p.i].indent)
+	}
+	return nil, nil
+}
+
+func (p *yamlParser) parseSeq(indent int) (any, error) {
+	out := make([]any, 0)
+	for p.skipBlank(); p.i < len(p.lines); p.skipBlank() {
+		l := p.lines[p.i]
+		if l.indent < indent || l.indent == indent && !isYamlSeqItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("yaml line %d: bad indentation", l.num)
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		var v any
+		var err error
+		switch {
+		case isYamlBlank(rest):
+			p.i++
+			v, err = p.parseChild(indent)
+		case isYamlSeqItem(rest) || isYamlKey(rest):
+			//Compact nested node e.g. "- a: 1", continue as if it's on its own line
+			off := len(l.text) - len(rest)
+			p.lines[p.i] = yamlLine{num: l.num, indent: indent + off, text: rest}
+			v, err = p.parseBlock(indent + off)
+		default:
+			p.i++
+			v, err = p.parseValue(rest, l.num, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func isYamlKey(text string) bool {
+	_, _, ok, _ := splitYamlKey(text)
+	return ok
+}
+
+func (p *yamlParser) parseMap(indent int) (any, error) {
+	out := make(map[string]any)
+	for p.skipBlank(); p.i < len(p.lines); p.skipBlank() {
+		l := p.lines[p.i]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent || isYamlSeqItem(l.text) {
+			return nil, fmt.Errorf("yaml line %d: bad indentation", l.num)
+		}
+		key, rest, ok, err := splitYamlKey(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d: %w", l.num, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("yaml line %d: expect key: value", l.num)
+		}
+		if _, exists := out[key]; exists {
+			return nil, fmt.Errorf("yaml line %d: duplicate key %q", l.num, key)
+		}
+		p.i++
+		var v any
+		if isYamlBlank(rest) {
+			p.skipBlank()
+			if p.i < len(p.lines) && p.lines[p.i].indent == indent && isYamlSeqItem(p.lines[p.i].text) {
+				v, err = p.parseSeq(indent) //Sequence is allowed at the same indent as its key
+			} else {
+				v, err = p.parseChild(indent)
+			}
+		} else {
+			v, err = p.parseValue(rest, l.num, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// splitYamlKey split "key: value", ok false if text is not mapping entry
+func splitYamlKey(text string) (key string, rest string, ok bool, err error) {
+	if text == "" || isYamlSeqItem(text) || strings.ContainsRune("[{#&*!|>?%@`", rune(text[0])) {
+		return
+	}
+	var after string
+	switch text[0] {
+	case '"', '\'':
+		end := yamlClosingQuote(text)
+		if end < 0 {
+			return
+		}
+		if key, err = unquoteYaml(text[:end+1]); err != nil {
+			return
+		}
+		after = text[end+1:]
+		if !strings.HasPrefix(after, ":") {
+			return "", "", false, nil
+		}
+	default:
+		idx := strings.Index(text, ": ")
+		if idx < 0 && strings.HasSuffix(text, ":") {
+			idx = len(text) - 1
+		}
+		if hash := strings.Index(text, " #"); idx < 0 || hash >= 0 && hash < idx {
+			return
+		}
+		key = strings.TrimSpace(text[:idx])
+		after = text[idx:]
+	}
+	if len(after) > 1 && after[1] != ' ' {
+		return "", "", false, nil
+	}
+	return key, strings.TrimSpace(after[1:]), true, nil
+}
+
+func yamlClosingQuote(s string) int {
+	if s[0] == '"' {
+		return closingQuote(s, '"')
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] == '\'' {
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func unquoteYaml(s string) (string, error) {
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	out, err := strconv.Unquote(strings.ReplaceAll(s, `\/`, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid double-quoted string %v", s)
+	}
+	return out, nil
+}
+
+// parseValue parse value after "key:" or "- ", parent is indent of the key or dash
+func (p *yamlParser) parseValue(text string, num int, parent int) (any, error) {
+	switch text[0] {
+	case '|', '>':
+		return p.parseBlockScalar(text, num, parent)
+	case '&', '*', '!':
+		return nil, fmt.Errorf("yaml line %d: anchor, alias and tag are not supported", num)
+	case '"', '\'':
+		end := yamlClosingQuote(text)
+		if end < 0 {
+			return nil, fmt.Errorf("yaml line %d: unterminated quoted string", num)
+		}
+		if !isYamlBlank(text[end+1:]) {
+			return nil, fmt.Errorf("yaml line %d: unexpected %q after quoted string", num, text[end+1:])
+		}
+		s, err := unquoteYaml(text[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d: %w", num, err)
+		}
+		return s, nil
+	case '[', '{':
+		f := &yamlFlow{s: text}
+		v, err := f.value()
+		if err == nil && !isYamlBlank(f.s[f.pos:]) {
+			err = fmt.Errorf("unexpected %q after flow collection", f.s[f.pos:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d: %w", num, err)
+		}
+		return v, nil
+	}
+	if idx := strings.Index(text, " #"); idx >= 0 {
+		text = text[:idx]
+	}
+	text = strings.TrimSpace(text)
+	multiline := false
+	for p.i < len(p.lines) && p.lines[p.i].indent > parent && !isYamlBlank(p.lines[p.i].text) { //Plain scalar continue on more indented lines
+		text += " " + p.lines[p.i].text
+		multiline = true
+		p.i++
+	}
+	if multiline {
+		return text, nil
+	}
+	return resolveYamlScalar(text), nil
+}
+
+func (p *yamlParser) parseBlockScalar(header string, num int, parent int) (any, error) {
+	if idx := strings.Index(header, " #"); idx >= 0 {
+		header = header[:idx]
+	}
+	header = strings.TrimSpace(header)
+	chomp := header[1:]
+	if len(chomp) > 1 || chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, fmt.Errorf("yaml line %d: unsupported block scalar header %q", num, header)
+	}
+	lines := make([]string, 0)
+	content := -1
+	for ; p.i < len(p.lines); p.i++ {
+		l := p.lines[p.i]
+		if strings.TrimSpace(l.text) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if content < 0 {
+			content = l.indent
+		}
+		if l.indent <= parent || l.indent < content {
+			break
+		}
+		lines = append(lines, strings.Repeat(" ", l.indent-content)+l.text)
+	}
+	trailing := 0
+	for trailing < len(lines) && lines[len(lines)-1-trailing] == "" {
+		trailing++
+	}
+	body := lines[:len(lines)-trailing]
+	var b strings.Builder
+	for i, line := range body {
+		switch {
+		case header[0] == '|':
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+		case line == "": //Folded, each empty line is a newline
+			b.WriteByte('\n')
+		case i > 0 && body[i-1] != "":
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(body[i-1], " ") { //More indented line is not folded
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(line)
+	}
+	switch {
+	case len(body) == 0 || chomp == "-":
+	case chomp == "+":
+		b.WriteString(strings.Repeat("\n", trailing+1))
+	default:
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func resolveYamlScalar(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if f, ok := parseNumber(s); ok && (!strings.ContainsAny(s, "iInN") || strings.Contains(s, ".")) { //inf/nan need dot in yaml
+		return f
+	}
+	return s
+}
+
+type yamlFlow struct {
+	s   string
+	pos int
+}
+
+func (f *yamlFlow) ws() {
+	for f.pos < len(f.s) && f.s[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *yamlFlow) value() (any, error) {
+	f.ws()
+	if f.pos >= len(f.s) {
+		return nil, fmt.Errorf("unterminated flow collection")
+	}
+	switch f.s[f.pos] {
+	case '[':
+		f.pos++
+		out := make([]any, 0)
+		for {
+			if f.ws(); f.pos < len(f.s) && f.s[f.pos] == ']' {
+				f.pos++
+				return out, nil
+			}
+			v, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+			if err = f.next(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		out := make(map[string]any)
+		for {
+			if f.ws(); f.pos < len(f.s) && f.s[f.pos] == '}' {
+				f.pos++
+				return out, nil
+			}
+			k, err := f.scalar(":,}")
+			if err != nil {
+				return nil, err
+			}
+			var v any
+			if f.ws(); f.pos < len(f.s) && f.s[f.pos] == ':' {
+				f.pos++
+				if f.ws(); f.pos < len(f.s) && f.s[f.pos] != ',' && f.s[f.pos] != '}' {
+					if v, err = f.value(); err != nil {
+						return nil, err
+					}
+				}
+			}
+			out[fmt.Sprintf("%v", k)] = v
+			if err = f.next('}'); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return f.scalar(",]}")
+}
+
+// next skip comma, leave closing for caller
+func (f *yamlFlow) next(closing byte) error {
+	f.ws()
+	if f.pos < len(f.s) {
+		switch f.s[f.pos] {
+		case ',':
+			f.pos++
+			return nil
+		case closing:
+			return nil
+		}
+	}
+	return fmt.Errorf("expect , or %c in flow collection", closing)
+}
+
+func (f *yamlFlow) scalar(stop string) (any, error) {
+	f.ws()
+	if f.pos < len(f.s) && (f.s[f.pos] == '"' || f.s[f.pos] == '\'') {
+		end := yamlClosingQuote(f.s[f.pos:])
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated quoted string")
+		}
+		s, err := unquoteYaml(f.s[f.pos : f.pos+end+1])
+		f.pos += end + 1
+		return s, err
+	}
+	start := f.pos
+	for f.pos < len(f.s) && !strings.ContainsRune(stop, rune(f.s[f.pos])) {
+		f.pos++
+	}
+	s := strings.TrimSpace(f.s[start:f.pos])
+	if strings.Contains(stop, ":") {
+		return s, nil //Key is always string
+	}
+	return resolveYamlScalar(s), nil
+}
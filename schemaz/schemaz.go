@@ -0,0 +1,626 @@
+package schemaz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zev-zakaryan/go-util/conv"
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+)
+
+var (
+	regEmail    = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	regUuid     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	regHostname = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// Schema is JSON Schema (draft 2020-12 subset), create by Parse or builder e.g. Object().Prop("id", Integer()).Require("id").
+//
+// Supported keywords: type, required, properties, additionalProperties, items, enum, const, pattern, minLength, maxLength,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minItems, maxItems and format (email, uri, date, date-time, time, uuid, ipv4, ipv6, hostname).
+// Other keywords are ignored. pattern is Go regexp (RE2) syntax.
+type Schema struct {
+	types        []string
+	required     []string
+	properties   map[string]*Schema
+	additional   *Schema //nil is allowed, use False() to disallow
+	items        *Schema
+	enum         []any
+	pattern      *regexp.Regexp
+	minLength    *int
+	maxLength    *int
+	minimum      *float64
+	maximum      *float64
+	exclusiveMin *float64
+	exclusiveMax *float64
+	minItems     *int
+	maxItems     *int
+	format       string
+	never        bool //false schema
+}
+
+// Violation is one validation failure. Path is dotted as conv.GetItems e.g. items.0.price, "" is root.
+// Key that is not a plain path segment e.g. with dot is an exact ^regex segment e.g. key "a.b" is ^a\.b$ with the dot as conv.DotAlternative, so conv.GetItems resolve the path.
+type Violation struct {
+	Path    string
+	Keyword string
+	Message string
+}
+
+func (v Violation) Error() string {
+	if v.Path == "" {
+		return fmt.Sprintf("(root): %v", v.Message)
+	}
+	return fmt.Sprintf("%v: %v", v.Path, v.Message)
+}
+
+// Violations is all failures of Validate.
+type Violations []Violation
+
+func (vs Violations) Error() string {
+	ss := make([]string, len(vs))
+	for i, v := range vs {
+		ss[i] = v.Error()
+	}
+	return strings.Join(ss, "; ")
+}
+
+func Any() *Schema {
+	return &Schema{}
+}
+
+// False is schema that nothing is valid, e.g. for additional properties.
+func False() *Schema {
+	return &Schema{never: true}
+}
+
+func Object() *Schema {
+	return &Schema{types: []string{TypeObject}}
+}
+
+func Array(items *Schema) *Schema {
+	return &Schema{types: []string{TypeArray}, items: items}
+}
+
+func String() *Schema {
+	return &Schema{types: []string{TypeString}}
+}
+
+func Number() *Schema {
+	return &Schema{types: []string{TypeNumber}}
+}
+
+func Integer() *Schema {
+	return &Schema{types: []string{TypeInteger}}
+}
+
+func Boolean() *Schema {
+	return &Schema{types: []string{TypeBoolean}}
+}
+
+func Null() *Schema {
+	return &Schema{types: []string{TypeNull}}
+}
+
+// Type set allowed types, replace the type of constructor.
+func (s *Schema) Type(types ...string) *Schema {
+	s.types = types
+	return s
+}
+
+// Nullable also allow null.
+func (s *Schema) Nullable() *Schema {
+	if len(s.types) > 0 {
+		s.types = append(s.types, TypeNull)
+	}
+	return s
+}
+
+func (s *Schema) Prop(name string, p *Schema) *Schema {
+	if s.properties == nil {
+		s.properties = make(map[string]*Schema)
+	}
+	s.properties[name] = p
+	return s
+}
+
+func (s *Schema) Require(names ...string) *Schema {
+	s.required = append(s.required, names...)
+	return s
+}
+
+// Additional set schema of properties not in Prop, False() to disallow.
+func (s *Schema) Additional(p *Schema) *Schema {
+	s.additional = p
+	return s
+}
+
+func (s *Schema) Items(items *Schema) *Schema {
+	s.items = items
+	return s
+}
+
+func (s *Schema) Enum(values ...any) *Schema {
+	s.enum = values
+	return s
+}
+
+// Pattern set regular expression that string must match (unanchored), panic if invalid as regexp.MustCompile.
+func (s *Schema) Pattern(expr string) *Schema {
+	s.pattern = regexp.MustCompile(expr)
+	return s
+}
+
+func (s *Schema) MinLength(n int) *Schema {
+	s.minLength = &n
+	return s
+}
+
+func (s *Schema) MaxLength(n int) *Schema {
+	s.maxLength = &n
+	return s
+}
+
+func (s *Schema) Min(f float64) *Schema {
+	s.minimum = &f
+	return s
+}
+
+func (s *Schema) Max(f float64) *Schema {
+	s.maximum = &f
+	return s
+}
+
+func (s *Schema) ExclusiveMin(f float64) *Schema {
+	s.exclusiveMin = &f
+	return s
+}
+
+func (s *Schema) ExclusiveMax(f float64) *Schema {
+	s.exclusiveMax = &f
+	return s
+}
+
+func (s *Schema) MinItems(n int) *Schema {
+	s.minItems = &n
+	return s
+}
+
+func (s *Schema) MaxItems(n int) *Schema {
+	s.maxItems = &n
+	return s
+}
+
+func (s *Schema) Format(format string) *Schema {
+	s.format = format
+	return s
+}
+
+// Parse JSON Schema from json string, []byte, io.Reader or map (see mapz.ToMapE). Boolean schema true or false is Any() or False().
+func Parse(schema any) (*Schema, error) {
+	if r, ok := schema.(io.Reader); ok {
+		bs, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		schema = bs
+	}
+	var b bool
+	switch v := schema.(type) {
+	case bool:
+		return parse(v, "")
+	case string:
+		if json.Unmarshal([]byte(v), &b) == nil {
+			return parse(b, "")
+		}
+	case []byte:
+		if json.Unmarshal(v, &b) == nil {
+			return parse(b, "")
+		}
+	}
+	m, err := mapz.ToMapE(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return parse(m, "")
+}
+
+func parse(v any, path string) (*Schema, error) {
+	switch vv := v.(type) {
+	case bool:
+		if vv {
+			return Any(), nil
+		}
+		return False(), nil
+	case map[string]any:
+		return parseObject(vv, path)
+	}
+	return nil, fmt.Errorf("invalid schema at %q: expect object or boolean, got %T", path, v)
+}
+
+func parseObject(m map[string]any, path string) (*Schema, error) {
+	s := &Schema{}
+	var err error
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []any:
+		for _, t1 := range t {
+			ts, ok := t1.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid schema at %q: type must be string", path)
+			}
+			s.types = append(s.types, ts)
+		}
+	default:
+		return nil, fmt.Errorf("invalid schema at %q: type must be string or array", path)
+	}
+	for _, t := range s.types {
+		switch t {
+		case TypeObject, TypeArray, TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeNull:
+		default:
+			return nil, fmt.Errorf("invalid schema at %q: unknown type %q", path, t)
+		}
+	}
+	if req, ok := m["required"]; ok {
+		rs, ok := req.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema at %q: required must be array", path)
+		}
+		for _, r := range rs {
+			name, ok := r.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid schema at %q: required must be array of strings", path)
+			}
+			s.required = append(s.required, name)
+		}
+	}
+	if props, ok := m["properties"].(map[string]any); ok {
+		s.properties = make(map[string]*Schema, len(props))
+		for k, p := range props {
+			if s.properties[k], err = parse(p, joinPath(path, k)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if ap, ok := m["additionalProperties"]; ok {
+		if s.additional, err = parse(ap, joinPath(path, "additionalProperties")); err != nil {
+			return nil, err
+		}
+	}
+	if items, ok := m["items"]; ok {
+		if s.items, err = parse(items, joinPath(path, "items")); err != nil {
+			return nil, err
+		}
+	}
+	if enum, ok := m["enum"].([]any); ok {
+		s.enum = enum
+	}
+	if c, ok := m["const"]; ok {
+		s.enum = []any{c}
+	}
+	if p, ok := m["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid schema at %q: %w", path, err)
+		}
+	}
+	s.format, _ = m["format"].(string)
+	ints := map[string]**int{"minLength": &s.minLength, "maxLength": &s.maxLength, "minItems": &s.minItems, "maxItems": &s.maxItems}
+	for k, dst := range ints {
+		if v, ok := m[k]; ok {
+			n, err := conv.To[int](v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema at %q: %v must be integer", path, k)
+			}
+			*dst = &n
+		}
+	}
+	floats := map[string]**float64{"minimum": &s.minimum, "maximum": &s.maximum, "exclusiveMinimum": &s.exclusiveMin, "exclusiveMaximum": &s.exclusiveMax}
+	for k, dst := range floats {
+		if v, ok := m[k]; ok {
+			f, ok := toFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("invalid schema at %q: %v must be number", path, k)
+			}
+			*dst = &f
+		}
+	}
+	return s, nil
+}
+
+// joinPath append key to dotted path as conv.GetItems segment, see Violation
+func joinPath(path string, key string) string {
+	if key == "" || key == "#" || key == "#k" || key == "#v" || strings.HasPrefix(key, "^") || strings.Contains(key, ".") {
+		key = strings.ReplaceAll(regexp.QuoteMeta(key), conv.DotAlternative, `\x{2024}`) //Literal DotAlternative must not become dot
+		key = "^" + strings.ReplaceAll(key, ".", conv.DotAlternative) + "$"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Validate return nil or Violations with all failures.
+func (s *Schema) Validate(v any) error {
+	var vs Violations
+	s.validate(v, "", &vs)
+	if len(vs) == 0 {
+		return nil
+	}
+	return vs
+}
+
+func (s *Schema) validate(v any, path string, vs *Violations) {
+	add := func(keyword string, format string, args ...any) {
+		*vs = append(*vs, Violation{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.never {
+		add("false", "value is not allowed")
+		return
+	}
+	t := typeOf(v)
+	if len(s.types) > 0 && !matchType(s.types, t, v) {
+		add("type", "expect %v, got %v", strings.Join(s.types, " or "), t)
+		return
+	}
+	if len(s.enum) > 0 {
+		found := false
+		for _, e := range s.enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("enum", "value %v is not one of %v", v, s.enum)
+		}
+	}
+	switch t {
+	case TypeString:
+		s.validateString(v.(string), add)
+	case TypeNumber, TypeInteger:
+		f, _ := toFloat(v)
+		s.validateNumber(f, add)
+	case TypeArray:
+		rv := reflect.ValueOf(v)
+		if s.minItems != nil && rv.Len() < *s.minItems {
+			add("minItems", "expect at least %v items, got %v", *s.minItems, rv.Len())
+		}
+		if s.maxItems != nil && rv.Len() > *s.maxItems {
+			add("maxItems", "expect at most %v items, got %v", *s.maxItems, rv.Len())
+		}
+		if s.items != nil {
+			for i := 0; i < rv.Len(); i++ {
+				s.items.validate(rv.Index(i).Interface(), joinPath(path, strconv.Itoa(i)), vs)
+			}
+		}
+	case TypeObject:
+		s.validateObject(v, path, vs, add)
+	}
+}
+
+func (s *Schema) validateString(str string, add func(string, string, ...any)) {
+	n := utf8.RuneCountInString(str)
+	if s.minLength != nil && n < *s.minLength {
+		add("minLength", "expect at least %v characters, got %v", *s.minLength, n)
+	}
+	if s.maxLength != nil && n > *s.maxLength {
+		add("maxLength", "expect at most %v characters, got %v", *s.maxLength, n)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		add("pattern", "value %q does not match %v", str, s.pattern)
+	}
+	if s.format != "" && !matchFormat(s.format, str) {
+		add("format", "value %q is not valid %v", str, s.format)
+	}
+}
+
+func (s *Schema) validateNumber(f float64, add func(string, string, ...any)) {
+	if s.minimum != nil && f < *s.minimum {
+		add("minimum", "expect >= %v, got %v", *s.minimum, f)
+	}
+	if s.maximum != nil && f > *s.maximum {
+		add("maximum", "expect <= %v, got %v", *s.maximum, f)
+	}
+	if s.exclusiveMin != nil && f <= *s.exclusiveMin {
+		add("exclusiveMinimum", "expect > %v, got %v", *s.exclusiveMin, f)
+	}
+	if s.exclusiveMax != nil && f >= *s.exclusiveMax {
+		add("exclusiveMaximum", "expect < %v, got %v", *s.exclusiveMax, f)
+	}
+}
+
+func (s *Schema) validateObject(v any, path string, vs *Violations, add func(string, string, ...any)) {
+	keys, get := objectAccess(v)
+	for _, r := range s.required {
+		if _, ok := get(r); !ok {
+			add("required", "missing required property %q", r)
+		}
+	}
+	for _, k := range keys {
+		val, _ := get(k)
+		if p, ok := s.properties[k]; ok {
+			p.validate(val, joinPath(path, k), vs)
+		} else if s.additional != nil {
+			s.additional.validate(val, joinPath(path, k), vs)
+		}
+	}
+}
+
+// objectAccess return sorted keys (or own order of conv.OrderedGetter) and getter of object
+func objectAccess(v any) ([]string, func(string) (any, bool)) {
+	if o, ok := v.(conv.OrderedGetter); ok {
+		return o.OrderedKeys(), o.OrderedGet
+	}
+	rv := reflect.ValueOf(v)
+	keys := make([]string, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys) //Need sort in golang for stable violation order
+	return keys, func(k string) (any, bool) {
+		val := rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()))
+		if !val.IsValid() {
+			return nil, false
+		}
+		return val.Interface(), true
+	}
+}
+
+func typeOf(v any) string {
+	if v == nil {
+		return TypeNull
+	}
+	if _, ok := v.(conv.OrderedGetter); ok {
+		return TypeObject
+	}
+	if _, ok := v.(json.Number); ok {
+		return TypeNumber
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return TypeNumber
+	case reflect.Slice, reflect.Array:
+		return TypeArray
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			return TypeObject
+		}
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return TypeNull
+		}
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func matchType(types []string, t string, v any) bool {
+	for _, want := range types {
+		if want == t {
+			return true
+		}
+		if want == TypeInteger && t == TypeNumber {
+			if f, ok := toFloat(v); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// equal compare as json value e.g. number of any go type is equal if the same value
+func equal(a, b any) bool {
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if okA || okB {
+		return okA && okB && fa == fb
+	}
+	t := typeOf(a)
+	if t != typeOf(b) {
+		return false
+	}
+	switch t {
+	case TypeArray:
+		ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+		if ra.Len() != rb.Len() {
+			return false
+		}
+		for i := 0; i < ra.Len(); i++ {
+			if !equal(ra.Index(i).Interface(), rb.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	case TypeObject:
+		keysA, getA := objectAccess(a)
+		keysB, getB := objectAccess(b)
+		if len(keysA) != len(keysB) {
+			return false
+		}
+		for _, k := range keysA {
+			va, _ := getA(k)
+			vb, ok := getB(k)
+			if !ok || !equal(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func matchFormat(format string, s string) bool {
+	switch format {
+	case "email":
+		return regEmail.MatchString(s)
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		return err == nil
+	case "uuid":
+		return regUuid.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	case "hostname":
+		return len(s) <= 253 && regHostname.MatchString(s)
+	}
+	return true //Unknown format is annotation only
+}
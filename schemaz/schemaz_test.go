@@ -0,0 +1,260 @@
+package schemaz
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zev-zakaryan/go-util/conv"
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "status", "items"],
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"status": {"enum": ["new", "paid"]},
+		"email": {"type": "string", "format": "email"},
+		"note": {"type": ["string", "null"], "maxLength": 5},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["sku"],
+				"properties": {
+					"sku": {"type": "string", "pattern": "^[A-Z]{3}-[0-9]+$"},
+					"price": {"type": "number", "exclusiveMinimum": 0}
+				},
+				"additionalProperties": false
+			}
+		}
+	}
+}`
+
+func orderBuilder() *Schema {
+	return Object().
+		Require("id", "status", "items").
+		Prop("id", Integer().Min(1)).
+		Prop("status", Any().Enum("new", "paid")).
+		Prop("email", String().Format("email")).
+		Prop("note", String().Nullable().MaxLength(5)).
+		Prop("items", Array(Object().
+			Require("sku").
+			Prop("sku", String().Pattern(`^[A-Z]{3}-[0-9]+$`)).
+			Prop("price", Number().ExclusiveMin(0)).
+			Additional(False())).MinItems(1))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	parsed, err := Parse(orderSchema)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		doc  string
+		want []Violation
+	}{
+		{
+			name: "valid",
+			doc:  `{"id":1,"status":"new","email":"a@b.co","note":null,"items":[{"sku":"ABC-1","price":1.5}],"extra":true}`,
+		},
+		{
+			name: "all violations with dotted path",
+			doc:  `{"id":1.5,"status":"x","email":"nope","note":"toolong","items":[{"sku":"abc","price":0,"x":1},{"price":"1"}]}`,
+			want: []Violation{
+				{Path: "email", Keyword: "format"},
+				{Path: "id", Keyword: "type"},
+				{Path: "items.0.price", Keyword: "exclusiveMinimum"},
+				{Path: "items.0.sku", Keyword: "pattern"},
+				{Path: "items.0.x", Keyword: "false"},
+				{Path: "items.1", Keyword: "required"},
+				{Path: "items.1.price", Keyword: "type"},
+				{Path: "note", Keyword: "maxLength"},
+				{Path: "status", Keyword: "enum"},
+			},
+		},
+		{
+			name: "required and min items",
+			doc:  `{"items":[]}`,
+			want: []Violation{
+				{Path: "", Keyword: "required"},
+				{Path: "", Keyword: "required"},
+				{Path: "items", Keyword: "minItems"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		for name, s := range map[string]*Schema{"parsed": parsed, "builder": orderBuilder()} {
+			t.Run(tt.name+" "+name, func(t *testing.T) {
+				err := s.Validate(mapz.ToMap(tt.doc))
+				var got []Violation
+				var vs Violations
+				if errors.As(err, &vs) {
+					for _, v := range vs {
+						got = append(got, Violation{Path: v.Path, Keyword: v.Keyword})
+					}
+				} else if err != nil {
+					t.Fatalf("Validate() error type %T", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Validate() = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateGoValues(t *testing.T) {
+	t.Parallel()
+	s := Object().
+		Prop("n", Integer().Max(10)).
+		Prop("tags", Array(String()).MaxItems(2)).
+		Prop("m", Object().Prop("x", Boolean())).
+		Prop("num", Integer()).
+		Prop("ordered", Object().Require("a"))
+	o := mapz.NewOrdered[string, any]()
+	o.Set("b", 1)
+	doc := map[string]any{
+		"n":       int64(11),
+		"tags":    []string{"a", "b", "c"},
+		"m":       map[string]bool{"x": true},
+		"num":     json.Number("3"),
+		"ordered": o,
+	}
+	err := s.Validate(doc)
+	var vs Violations
+	if !errors.As(err, &vs) || len(vs) != 3 {
+		t.Fatalf("Validate() = %v, want 3 violations", err)
+	}
+	if vs[0].Path != "n" || vs[1].Path != "ordered" || vs[2].Path != "tags" {
+		t.Errorf("Validate() = %v", vs)
+	}
+	if err.Error() != `n: expect <= 10, got 11; ordered: missing required property "a"; tags: expect at most 2 items, got 3` {
+		t.Errorf("Error() = %v", err.Error())
+	}
+	if err := Integer().Validate(3.0); err != nil {
+		t.Errorf("Validate() integer as float = %v", err)
+	}
+	if err := Null().Validate(nil); err != nil {
+		t.Errorf("Validate() null = %v", err)
+	}
+	if err := String().Validate(nil); err == nil || err.Error() != "(root): expect string, got null" {
+		t.Errorf("Validate() root = %v", err)
+	}
+	if err := Any().Enum(1, "a").Validate(1.0); err != nil {
+		t.Errorf("Validate() enum number = %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		format string
+		valid  []string
+		fail   []string
+	}{
+		{format: "email", valid: []string{"a.b@c.io"}, fail: []string{"a@b", "a b@c.io"}},
+		{format: "uri", valid: []string{"https://x.y/z?q=1"}, fail: []string{"/relative"}},
+		{format: "date", valid: []string{"2024-02-29"}, fail: []string{"2023-02-29", "2024-1-1"}},
+		{format: "date-time", valid: []string{"2024-01-02T03:04:05Z", "2024-01-02T03:04:05.1+07:00"}, fail: []string{"2024-01-02 03:04:05"}},
+		{format: "time", valid: []string{"03:04:05Z"}, fail: []string{"25:00:00Z"}},
+		{format: "uuid", valid: []string{"123e4567-e89b-12d3-a456-426614174000"}, fail: []string{"123e4567e89b12d3a456426614174000"}},
+		{format: "ipv4", valid: []string{"10.0.0.1"}, fail: []string{"::1", "10.0.0"}},
+		{format: "ipv6", valid: []string{"::1", "2001:db8::1"}, fail: []string{"10.0.0.1"}},
+		{format: "hostname", valid: []string{"api.example.com", "localhost"}, fail: []string{"-x.com", "a_b.com"}},
+		{format: "unknown", valid: []string{"anything"}},
+	}
+	for _, tt := range tests {
+		s := String().Format(tt.format)
+		for _, v := range tt.valid {
+			if err := s.Validate(v); err != nil {
+				t.Errorf("format %v %q error = %v", tt.format, v, err)
+			}
+		}
+		for _, v := range tt.fail {
+			if err := s.Validate(v); err == nil {
+				t.Errorf("format %v %q expect error", tt.format, v)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		schema  any
+		wantErr bool
+	}{
+		{name: "map", schema: map[string]any{"type": "string", "const": "x"}},
+		{name: "boolean subschema", schema: `{"properties":{"a":true,"b":false}}`},
+		{name: "invalid json", schema: `{`, wantErr: true},
+		{name: "unknown type", schema: `{"type":"int"}`, wantErr: true},
+		{name: "invalid type", schema: `{"type":1}`, wantErr: true},
+		{name: "invalid pattern", schema: `{"pattern":"("}`, wantErr: true},
+		{name: "invalid minLength", schema: `{"minLength":"x"}`, wantErr: true},
+		{name: "invalid minimum", schema: `{"minimum":"x"}`, wantErr: true},
+		{name: "invalid subschema", schema: `{"items":1}`, wantErr: true},
+		{name: "required not array", schema: `{"required":"id"}`, wantErr: true},
+		{name: "required not string", schema: `{"required":["id",1]}`, wantErr: true},
+		{name: "root true", schema: `true`},
+		{name: "root false bytes", schema: []byte(" false ")},
+		{name: "root bool", schema: true},
+		{name: "root reader", schema: strings.NewReader(`{"type":"string"}`)},
+		{name: "root number", schema: `1`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.schema); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	s, _ := Parse(`{"properties":{"a":true,"b":false},"const":{"a":1}}`)
+	if err := s.Validate(map[string]any{"a": 1}); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	if err := s.Validate(map[string]any{"a": 1, "b": 1}); err == nil {
+		t.Errorf("Validate() expect error for false schema")
+	}
+	for schema, valid := range map[string]bool{"true": true, "false": false} {
+		s, err := Parse(schema)
+		if err != nil {
+			t.Fatalf("Parse(%v) error = %v", schema, err)
+		}
+		if err := s.Validate(map[string]any{"a": 1}); (err == nil) != valid {
+			t.Errorf("Parse(%v).Validate() = %v", schema, err)
+		}
+	}
+}
+
+func TestViolationPathSpecialKeys(t *testing.T) {
+	t.Parallel()
+	keys := []string{"a.b", "^x", "#", "#k", "", "x" + conv.DotAlternative + "y", "plain"}
+	s, doc := Object(), map[string]any{"a": map[string]any{"b": "y"}, "xay": "y"}
+	for _, k := range keys {
+		s.Prop(k, Integer())
+		doc[k] = "x"
+	}
+	var vs Violations
+	if err := s.Validate(doc); !errors.As(err, &vs) || len(vs) != len(keys) {
+		t.Fatalf("Validate() = %v", err)
+	}
+	paths := map[string]bool{}
+	for _, v := range vs {
+		paths[v.Path] = true
+		if got := conv.GetItems(doc, v.Path); len(got) != 1 || got[0] != "x" {
+			t.Errorf("GetItems(%q) = %v, want [x]", v.Path, got)
+		}
+	}
+	if !paths["plain"] || !paths[`^a\`+conv.DotAlternative+`b$`] {
+		t.Errorf("Paths = %v", paths)
+	}
+}
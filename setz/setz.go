@@ -0,0 +1,216 @@
+// Package setz is generic set of comparable values based on map[T]struct{}.
+package setz
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+// Set is unordered collection of unique values, create by New, FromSlice or FromKeys as nil Set can't Add.
+// It is map so len, range and make work, not safe for concurrent writes.
+type Set[T comparable] map[T]struct{}
+
+// New return set of values.
+func New[T comparable](values ...T) Set[T] {
+	return FromSlice(values)
+}
+
+// FromSlice return set of elements of a, duplicates are removed.
+func FromSlice[T comparable](a []T) Set[T] {
+	s := make(Set[T], len(a))
+	for _, v := range a {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+// FromKeys return set of keys of m.
+func FromKeys[T comparable, V any](m map[T]V) Set[T] {
+	s := make(Set[T], len(m))
+	for k := range m {
+		s[k] = struct{}{}
+	}
+	return s
+}
+
+// Add values to s.
+func (s Set[T]) Add(values ...T) {
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+}
+
+// Remove values from s, missing values are ignored.
+func (s Set[T]) Remove(values ...T) {
+	for _, v := range values {
+		delete(s, v)
+	}
+}
+
+// Contains return true if v is in s.
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// ContainsAll return true if all values are in s, true for no values.
+func (s Set[T]) ContainsAll(values ...T) bool {
+	for _, v := range values {
+		if !s.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny return true if any of values is in s.
+func (s Set[T]) ContainsAny(values ...T) bool {
+	for _, v := range values {
+		if s.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len return number of values.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Clone return copy of s.
+func (s Set[T]) Clone() Set[T] {
+	out := make(Set[T], len(s))
+	for v := range s {
+		out[v] = struct{}{}
+	}
+	return out
+}
+
+// Values return values of s, sorted by less if specified otherwise in random order as map iteration e.g. s.Values(mapz.Less[string]).
+func (s Set[T]) Values(less ...func(a, b T) bool) []T {
+	return mapz.Keys(s, less...)
+}
+
+// All iterate values sorted by less if specified, stop when yield return false.
+func (s Set[T]) All(less ...func(a, b T) bool) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for _, v := range s.Values(less...) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Union return new set of values in s or any of others.
+func (s Set[T]) Union(others ...Set[T]) Set[T] {
+	out := s.Clone()
+	for _, o := range others {
+		for v := range o {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Intersection return new set of values in s and all of others.
+func (s Set[T]) Intersection(others ...Set[T]) Set[T] {
+	out := make(Set[T])
+	for v := range s {
+		in := true
+		for _, o := range others {
+			if !o.Contains(v) {
+				in = false
+				break
+			}
+		}
+		if in {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Difference return new set of values in s but not in any of others.
+func (s Set[T]) Difference(others ...Set[T]) Set[T] {
+	out := make(Set[T])
+	for v := range s {
+		in := false
+		for _, o := range others {
+			if o.Contains(v) {
+				in = true
+				break
+			}
+		}
+		if !in {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// SymmetricDifference return new set of values in exactly one of s and o.
+func (s Set[T]) SymmetricDifference(o Set[T]) Set[T] {
+	out := s.Difference(o)
+	for v := range o {
+		if !s.Contains(v) {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// IsSubset return true if all values of s are in o.
+func (s Set[T]) IsSubset(o Set[T]) bool {
+	if len(s) > len(o) {
+		return false
+	}
+	for v := range s {
+		if !o.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset return true if all values of o are in s.
+func (s Set[T]) IsSuperset(o Set[T]) bool {
+	return o.IsSubset(s)
+}
+
+// Equal return true if s and o have the same values.
+func (s Set[T]) Equal(o Set[T]) bool {
+	return len(s) == len(o) && s.IsSubset(o)
+}
+
+// MarshalJSON encode s as json array, sorted by encoded values for stable output.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	items := make([][]byte, 0, len(s))
+	for v := range s {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, bs)
+	}
+	sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i], items[j]) < 0 })
+	return append(append([]byte{'['}, bytes.Join(items, []byte{','})...), ']'), nil
+}
+
+// UnmarshalJSON decode json array to s, duplicates are removed. null is nil Set.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var a []T
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	if a == nil {
+		*s = nil
+		return nil
+	}
+	*s = FromSlice(a)
+	return nil
+}
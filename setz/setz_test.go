@@ -0,0 +1,96 @@
+package setz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+	s := New(3, 1, 2, 3)
+	if s.Len() != 3 || !s.Contains(1) || s.Contains(4) {
+		t.Errorf("New() = %v", s)
+	}
+	s.Add(4, 5)
+	s.Remove(1, 9)
+	if got := s.Values(mapz.Less[int]); !reflect.DeepEqual(got, []int{2, 3, 4, 5}) {
+		t.Errorf("Values() = %v", got)
+	}
+	if !s.ContainsAll(2, 5) || s.ContainsAll(2, 1) || !s.ContainsAll() {
+		t.Errorf("ContainsAll() wrong")
+	}
+	if !s.ContainsAny(1, 5) || s.ContainsAny(1) {
+		t.Errorf("ContainsAny() wrong")
+	}
+	c := s.Clone()
+	c.Add(10)
+	if s.Contains(10) {
+		t.Errorf("Clone() share memory")
+	}
+	var got []int
+	FromKeys(map[int]string{2: "b", 1: "a", 3: "c"}).All(mapz.Less[int])(func(v int) bool {
+		got = append(got, v)
+		return v < 2
+	})
+	if !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("All() = %v", got)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	t.Parallel()
+	a, b, c := New(1, 2, 3, 4), New(3, 4, 5), New(4, 6)
+	tests := []struct {
+		name string
+		got  Set[int]
+		want Set[int]
+	}{
+		{name: "union", got: a.Union(b, c), want: New(1, 2, 3, 4, 5, 6)},
+		{name: "union none", got: a.Union(), want: a},
+		{name: "intersection", got: a.Intersection(b), want: New(3, 4)},
+		{name: "intersection many", got: a.Intersection(b, c), want: New(4)},
+		{name: "difference", got: a.Difference(b), want: New(1, 2)},
+		{name: "difference many", got: a.Difference(b, New(1)), want: New(2)},
+		{name: "symmetric difference", got: a.SymmetricDifference(b), want: New(1, 2, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+	if !New(3, 4).IsSubset(a) || a.IsSubset(b) || !a.IsSuperset(New(1)) || !New[int]().IsSubset(nil) {
+		t.Errorf("IsSubset() wrong")
+	}
+	if a.Equal(New(1, 2, 3, 5)) {
+		t.Errorf("Equal() wrong")
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	t.Parallel()
+	type doc struct {
+		Tags Set[string] `json:"tags"`
+	}
+	bs, err := json.Marshal(doc{Tags: FromSlice([]string{"go", "db", "api", "go"})})
+	if err != nil || string(bs) != `{"tags":["api","db","go"]}` {
+		t.Errorf("Marshal() = %s, %v", bs, err)
+	}
+	if bs, _ := json.Marshal(New[int]()); string(bs) != "[]" {
+		t.Errorf("Marshal() empty = %s", bs)
+	}
+	var d doc
+	if err := json.Unmarshal([]byte(`{"tags":["x","y","x"]}`), &d); err != nil || !d.Tags.Equal(New("x", "y")) {
+		t.Errorf("Unmarshal() = %v, %v", d.Tags, err)
+	}
+	if err := json.Unmarshal([]byte(`{"tags":null}`), &d); err != nil || d.Tags != nil {
+		t.Errorf("Unmarshal() null = %v, %v", d.Tags, err)
+	}
+	if err := json.Unmarshal([]byte(`{"tags":[1]}`), &d); err == nil {
+		t.Errorf("Unmarshal() expect error for wrong type")
+	}
+}
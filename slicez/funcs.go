@@ -0,0 +1,225 @@
+package slicez
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Pair is element of Zip.
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Map return new slice of f applied to each element.
+func Map[T any, U any](a []T, f func(T) U) []U {
+	out := make([]U, len(a))
+	for i := range a {
+		out[i] = f(a[i])
+	}
+	return out
+}
+
+// Filter return new slice of elements that keep return true, in the same order.
+func Filter[T any](a []T, keep func(T) bool) []T {
+	out := make([]T, 0)
+	for _, v := range a {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce fold elements from left to right into accumulator starting from init e.g. sum is Reduce(a, 0, func(s, v int) int { return s + v }).
+func Reduce[T any, A any](a []T, init A, f func(acc A, v T) A) A {
+	acc := init
+	for _, v := range a {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// FlatMap is Map that f return slice, then Flatten.
+func FlatMap[T any, U any](a []T, f func(T) []U) []U {
+	out := make([]U, 0, len(a))
+	for _, v := range a {
+		out = append(out, f(v)...)
+	}
+	return out
+}
+
+// Chunk split a into consecutive slices of size, the last one may be shorter. Chunks share memory with a but have capacity capped, so append to a chunk does not overwrite the next one.
+//
+// Panic if size < 1.
+func Chunk[T any](a []T, size int) [][]T {
+	if size < 1 {
+		panic(fmt.Sprintf("slicez: invalid chunk size %d", size))
+	}
+	out := make([][]T, 0, (len(a)+size-1)/size)
+	for i := 0; i < len(a); i += size {
+		j := i + size
+		if j > len(a) {
+			j = len(a)
+		}
+		out = append(out, a[i:j:j])
+	}
+	return out
+}
+
+// Window return sliding windows of size moving by 1 e.g. [1 2 3 4] size 2 is [[1 2] [2 3] [3 4]], empty if size > len(a). Windows share memory with a and have capacity capped.
+//
+// Panic if size < 1.
+func Window[T any](a []T, size int) [][]T {
+	if size < 1 {
+		panic(fmt.Sprintf("slicez: invalid window size %d", size))
+	}
+	if size > len(a) {
+		return [][]T{}
+	}
+	out := make([][]T, 0, len(a)-size+1)
+	for i := 0; i+size <= len(a); i++ {
+		out = append(out, a[i:i+size:i+size])
+	}
+	return out
+}
+
+// Partition split a into elements that match return true and the rest, both keep the order.
+func Partition[T any](a []T, match func(T) bool) (matched []T, rest []T) {
+	matched, rest = make([]T, 0), make([]T, 0)
+	for _, v := range a {
+		if match(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// GroupBy group elements by key, each group keep the order of a.
+func GroupBy[T any, K comparable](a []T, key func(T) K) map[K][]T {
+	out := make(map[K][]T)
+	for _, v := range a {
+		k := key(v)
+		out[k] = append(out[k], v)
+	}
+	return out
+}
+
+// Uniq return new slice without duplicates, keep the first occurrence.
+func Uniq[T comparable](a []T) []T {
+	return UniqBy(a, func(v T) T { return v })
+}
+
+// UniqBy is Uniq that compare by key e.g. case-insensitive by strings.ToLower.
+func UniqBy[T any, K comparable](a []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(a))
+	out := make([]T, 0)
+	for _, v := range a {
+		k := key(v)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Zip pair elements of a and b by index, truncated to the shorter one.
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	out := make([]Pair[A, B], n)
+	for i := range out {
+		out[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return out
+}
+
+// Unzip is reverse of Zip.
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(pairs)), make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
+
+// Difference return unique elements of a that are not in b, in the order of a.
+func Difference[T comparable](a []T, b []T) []T {
+	exclude := toSet(b)
+	return Uniq(Filter(a, func(v T) bool {
+		_, ok := exclude[v]
+		return !ok
+	}))
+}
+
+// Intersection return unique elements of a that are also in b, in the order of a.
+func Intersection[T comparable](a []T, b []T) []T {
+	include := toSet(b)
+	return Uniq(Filter(a, func(v T) bool {
+		_, ok := include[v]
+		return ok
+	}))
+}
+
+// Union return unique elements of all slices in order of first occurrence.
+func Union[T comparable](a ...[]T) []T {
+	return Uniq(Flatten(a))
+}
+
+// Flatten concatenate slices into a new one.
+func Flatten[T any](a [][]T) []T {
+	n := 0
+	for _, v := range a {
+		n += len(v)
+	}
+	out := make([]T, 0, n)
+	for _, v := range a {
+		out = append(out, v...)
+	}
+	return out
+}
+
+// Reverse return new slice in reverse order, a is not modified.
+func Reverse[T any](a []T) []T {
+	out := make([]T, len(a))
+	for i, v := range a {
+		out[len(a)-1-i] = v
+	}
+	return out
+}
+
+// IndexFunc return index of the first element that f return true, -1 if none.
+func IndexFunc[T any](a []T, f func(T) bool) int {
+	for i, v := range a {
+		if f(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Shuffle return new slice in random order by Fisher-Yates, a is not modified.
+// Use rng for reproducible order e.g. rand.New(rand.NewSource(1)) in tests, nil use the global source.
+func Shuffle[T any](a []T, rng *rand.Rand) []T {
+	out := Clone(a)
+	swap := func(i, j int) { out[i], out[j] = out[j], out[i] }
+	if rng == nil {
+		rand.Shuffle(len(out), swap)
+	} else {
+		rng.Shuffle(len(out), swap)
+	}
+	return out
+}
+
+func toSet[T comparable](a []T) map[T]struct{} {
+	out := make(map[T]struct{}, len(a))
+	for _, v := range a {
+		out[v] = struct{}{}
+	}
+	return out
+}
@@ -0,0 +1,230 @@
+package slicez
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMapFilterReduce(t *testing.T) {
+	t.Parallel()
+	a := []int{1, 2, 3, 4, 5}
+	if got := Map(a, strconv.Itoa); !reflect.DeepEqual(got, []string{"1", "2", "3", "4", "5"}) {
+		t.Errorf("Map() = %v", got)
+	}
+	if got := Filter(a, func(v int) bool { return v%2 == 1 }); !reflect.DeepEqual(got, []int{1, 3, 5}) {
+		t.Errorf("Filter() = %v", got)
+	}
+	if got := Filter([]int(nil), func(v int) bool { return true }); got == nil || len(got) != 0 {
+		t.Errorf("Filter() nil = %#v, want empty", got)
+	}
+	if got := Reduce(a, "", func(acc string, v int) string { return acc + strconv.Itoa(v) }); got != "12345" {
+		t.Errorf("Reduce() = %v", got)
+	}
+	if got := FlatMap([]string{"a b", "", "c"}, strings.Fields); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("FlatMap() = %v", got)
+	}
+	if got := IndexFunc(a, func(v int) bool { return v > 2 }); got != 2 {
+		t.Errorf("IndexFunc() = %v", got)
+	}
+	if got := IndexFunc(a, func(v int) bool { return v > 5 }); got != -1 {
+		t.Errorf("IndexFunc() = %v, want -1", got)
+	}
+}
+
+func TestChunkWindow(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		a          []int
+		size       int
+		wantChunk  [][]int
+		wantWindow [][]int
+	}{
+		{
+			name:       "uneven",
+			a:          []int{1, 2, 3, 4, 5},
+			size:       2,
+			wantChunk:  [][]int{{1, 2}, {3, 4}, {5}},
+			wantWindow: [][]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}},
+		},
+		{
+			name:       "size is length",
+			a:          []int{1, 2, 3},
+			size:       3,
+			wantChunk:  [][]int{{1, 2, 3}},
+			wantWindow: [][]int{{1, 2, 3}},
+		},
+		{
+			name:       "size over length",
+			a:          []int{1},
+			size:       2,
+			wantChunk:  [][]int{{1}},
+			wantWindow: [][]int{},
+		},
+		{
+			name:       "empty",
+			a:          nil,
+			size:       1,
+			wantChunk:  [][]int{},
+			wantWindow: [][]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.a, tt.size); !reflect.DeepEqual(got, tt.wantChunk) {
+				t.Errorf("Chunk() = %v, want %v", got, tt.wantChunk)
+			}
+			if got := Window(tt.a, tt.size); !reflect.DeepEqual(got, tt.wantWindow) {
+				t.Errorf("Window() = %v, want %v", got, tt.wantWindow)
+			}
+		})
+	}
+	a := []int{1, 2, 3, 4}
+	chunks := Chunk(a, 2)
+	_ = append(chunks[0], 9)
+	if a[2] != 3 {
+		t.Errorf("Chunk() append overwrite next chunk")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Chunk() expect panic for size 0")
+		}
+	}()
+	Chunk(a, 0)
+}
+
+func TestPartitionGroupBy(t *testing.T) {
+	t.Parallel()
+	words := []string{"apple", "bob", "avocado", "cat", "banana"}
+	matched, rest := Partition(words, func(s string) bool { return len(s) > 3 })
+	if !reflect.DeepEqual(matched, []string{"apple", "avocado", "banana"}) || !reflect.DeepEqual(rest, []string{"bob", "cat"}) {
+		t.Errorf("Partition() = %v, %v", matched, rest)
+	}
+	want := map[byte][]string{'a': {"apple", "avocado"}, 'b': {"bob", "banana"}, 'c': {"cat"}}
+	if got := GroupBy(words, func(s string) byte { return s[0] }); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy() = %v, want %v", got, want)
+	}
+}
+
+func TestUniqAndSets(t *testing.T) {
+	t.Parallel()
+	if got := Uniq([]int{3, 1, 3, 2, 1}); !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Errorf("Uniq() = %v", got)
+	}
+	if got := UniqBy([]string{"Go", "go", "GO", "rust"}, strings.ToLower); !reflect.DeepEqual(got, []string{"Go", "rust"}) {
+		t.Errorf("UniqBy() = %v", got)
+	}
+	a, b := []int{1, 2, 2, 3, 4}, []int{4, 2, 5}
+	if got := Difference(a, b); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if got := Intersection(a, b); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Intersection() = %v", got)
+	}
+	if got := Union(a, b, nil); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := Flatten([][]int{{1}, nil, {2, 3}}); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Flatten() = %v", got)
+	}
+}
+
+func TestZipUnzip(t *testing.T) {
+	t.Parallel()
+	pairs := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	if want := []Pair[string, int]{{"a", 1}, {"b", 2}}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("Zip() = %v, want %v", pairs, want)
+	}
+	keys, values := Unzip(pairs)
+	if !reflect.DeepEqual(keys, []string{"a", "b"}) || !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("Unzip() = %v, %v", keys, values)
+	}
+}
+
+func TestReverseShuffle(t *testing.T) {
+	t.Parallel()
+	a := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	if got := Reverse(a); !reflect.DeepEqual(got, []int{8, 7, 6, 5, 4, 3, 2, 1}) || a[0] != 1 {
+		t.Errorf("Reverse() = %v, a = %v", got, a)
+	}
+	got1 := Shuffle(a, rand.New(rand.NewSource(42)))
+	got2 := Shuffle(a, rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(got1, got2) {
+		t.Errorf("Shuffle() same seed = %v and %v", got1, got2)
+	}
+	if !reflect.DeepEqual(a, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("Shuffle() modified a = %v", a)
+	}
+	got3 := Shuffle(a, nil)
+	sort.Ints(got1)
+	sort.Ints(got3)
+	if !reflect.DeepEqual(got1, a) || !reflect.DeepEqual(got3, a) {
+		t.Errorf("Shuffle() is not permutation %v, %v", got1, got3)
+	}
+}
+
+var benchInts = func() []int {
+	out := make([]int, 10000)
+	for i := range out {
+		out[i] = i % 1000
+	}
+	return out
+}()
+
+func BenchmarkMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = Map(benchInts, func(v int) int { return v * 2 })
+	}
+}
+
+func BenchmarkMapLoop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		out := make([]int, len(benchInts))
+		for j, v := range benchInts {
+			out[j] = v * 2
+		}
+		_ = out
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = Filter(benchInts, func(v int) bool { return v%2 == 0 })
+	}
+}
+
+func BenchmarkFilterLoop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		out := make([]int, 0)
+		for _, v := range benchInts {
+			if v%2 == 0 {
+				out = append(out, v)
+			}
+		}
+		_ = out
+	}
+}
+
+func BenchmarkUniq(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = Uniq(benchInts)
+	}
+}
+
+func BenchmarkUniqLoop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		seen := make(map[int]struct{}, len(benchInts))
+		out := make([]int, 0)
+		for _, v := range benchInts {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				out = append(out, v)
+			}
+		}
+		_ = out
+	}
+}
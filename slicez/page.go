@@ -0,0 +1,135 @@
+package slicez
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/zev-zakaryan/go-util/conv"
+	"github.com/zev-zakaryan/go-util/stringz"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor and PaginateCursor for malformed or tampered cursor.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// crcLen is length of stringz.ToCrc32
+const crcLen = 8
+
+// Page is one page of items with metadata for API response. Items share memory with the source slice but have capacity capped.
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Page    int  `json:"page"` //From 1
+	Size    int  `json:"size"`
+	Offset  int  `json:"offset"`
+	Total   int  `json:"total"`
+	Pages   int  `json:"pages"`
+	HasPrev bool `json:"hasPrev"`
+	HasNext bool `json:"hasNext"`
+}
+
+// CursorPage is one page of PaginateCursor, pass Next as cursor to get the next page.
+type CursorPage[T any] struct {
+	Items   []T    `json:"items"`
+	Total   int    `json:"total"`
+	Next    string `json:"next,omitempty"` //Cursor after the last item, "" if no next page
+	HasNext bool   `json:"hasNext"`
+}
+
+// Paginate return page of items from 1. page < 1 is 1 and size < 1 is 1. Page after the last one has no items, Offset is math.MaxInt if it overflow.
+func Paginate[T any](items []T, page, size int) Page[T] {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 1
+	}
+	offset := (page - 1) * size
+	if offset/size != page-1 { //Overflow
+		offset = math.MaxInt
+	}
+	out := OffsetLimit(items, offset, size)
+	out.Page = page
+	return out
+}
+
+// OffsetLimit return up to limit items from offset. offset < 0 is 0 and limit < 0 is 0, offset past the end has no items but is kept in Offset.
+// Page is the page number of offset if limit > 0 e.g. offset 20 limit 10 is page 3, HasPrev is true if offset > 0.
+func OffsetLimit[T any](items []T, offset, limit int) Page[T] {
+	total := len(items)
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	start := offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if limit < total-start {
+		end = start + limit
+	}
+	out := Page[T]{
+		Items:   items[start:end:end],
+		Size:    limit,
+		Offset:  offset,
+		Total:   total,
+		HasPrev: offset > 0,
+		HasNext: end < total,
+	}
+	if limit > 0 {
+		out.Page = offset/limit + conv.Ternary(offset/limit < math.MaxInt, 1, 0) //Clamp at MaxInt
+		out.Pages = total/limit + conv.Ternary(total%limit > 0, 1, 0)
+	}
+	return out
+}
+
+// EncodeCursor return opaque url-safe cursor of key with stringz.ToCrc32 checksum.
+// Checksum detect corrupted or hand-edited cursor, it is not a signature, use hashz.Hmac for untrusted clients if key is sensitive.
+func EncodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + stringz.ToCrc32(key)))
+}
+
+// DecodeCursor return key of cursor from EncodeCursor, ErrInvalidCursor if malformed or checksum mismatch.
+func DecodeCursor(cursor string) (string, error) {
+	bs, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(bs) < crcLen {
+		return "", ErrInvalidCursor
+	}
+	key, sum := string(bs[:len(bs)-crcLen]), string(bs[len(bs)-crcLen:])
+	if stringz.ToCrc32(key) != sum {
+		return "", ErrInvalidCursor
+	}
+	return key, nil
+}
+
+// PaginateCursor return up to size items after the item of cursor, "" cursor is the first page. size < 1 is 1.
+// key must be unique and stable for items in stable order e.g. ID of sorted rows. Error wraps ErrInvalidCursor if cursor is invalid or its item is not found.
+//
+// The item of cursor is found by linear scan of key, O(n) per page. If that item was removed since the cursor was made, e.g. row deleted,
+// the error wraps ErrInvalidCursor and the client must restart from "", so for changing data query the rows after the key instead.
+func PaginateCursor[T any](items []T, cursor string, size int, key func(T) string) (CursorPage[T], error) {
+	if size < 1 {
+		size = 1
+	}
+	start := 0
+	if cursor != "" {
+		k, err := DecodeCursor(cursor)
+		if err != nil {
+			return CursorPage[T]{}, err
+		}
+		i := IndexFunc(items, func(v T) bool { return key(v) == k })
+		if i < 0 {
+			return CursorPage[T]{}, fmt.Errorf("%w: key %q not found", ErrInvalidCursor, k)
+		}
+		start = i + 1
+	}
+	p := OffsetLimit(items, start, size)
+	out := CursorPage[T]{Items: p.Items, Total: p.Total, HasNext: p.HasNext}
+	if p.HasNext {
+		out.Next = EncodeCursor(key(p.Items[len(p.Items)-1]))
+	}
+	return out, nil
+}
@@ -0,0 +1,126 @@
+package slicez
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	tests := []struct {
+		name string
+		page int
+		size int
+		want Page[int]
+	}{
+		{name: "first", page: 1, size: 3, want: Page[int]{Items: []int{1, 2, 3}, Page: 1, Size: 3, Offset: 0, Total: 7, Pages: 3, HasNext: true}},
+		{name: "middle", page: 2, size: 3, want: Page[int]{Items: []int{4, 5, 6}, Page: 2, Size: 3, Offset: 3, Total: 7, Pages: 3, HasPrev: true, HasNext: true}},
+		{name: "last partial", page: 3, size: 3, want: Page[int]{Items: []int{7}, Page: 3, Size: 3, Offset: 6, Total: 7, Pages: 3, HasPrev: true}},
+		{name: "exact last", page: 1, size: 7, want: Page[int]{Items: []int{1, 2, 3, 4, 5, 6, 7}, Page: 1, Size: 7, Total: 7, Pages: 1}},
+		{name: "after last", page: 4, size: 3, want: Page[int]{Items: []int{}, Page: 4, Size: 3, Offset: 9, Total: 7, Pages: 3, HasPrev: true}},
+		{name: "clamp page and size", page: 0, size: 0, want: Page[int]{Items: []int{1}, Page: 1, Size: 1, Total: 7, Pages: 7, HasNext: true}},
+		{name: "overflow", page: math.MaxInt, size: math.MaxInt, want: Page[int]{Items: []int{}, Page: math.MaxInt, Size: math.MaxInt, Offset: math.MaxInt, Total: 7, Pages: 1, HasPrev: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Paginate(items, tt.page, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Paginate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if got := Paginate([]int(nil), 1, 10); got.Total != 0 || got.Pages != 0 || len(got.Items) != 0 || got.HasNext {
+		t.Errorf("Paginate() empty = %+v", got)
+	}
+}
+
+func TestOffsetLimit(t *testing.T) {
+	t.Parallel()
+	items := []string{"a", "b", "c", "d", "e"}
+	tests := []struct {
+		name   string
+		offset int
+		limit  int
+		want   Page[string]
+	}{
+		{name: "middle", offset: 1, limit: 2, want: Page[string]{Items: []string{"b", "c"}, Page: 1, Size: 2, Offset: 1, Total: 5, Pages: 3, HasPrev: true, HasNext: true}},
+		{name: "limit over end", offset: 3, limit: 10, want: Page[string]{Items: []string{"d", "e"}, Page: 1, Size: 10, Offset: 3, Total: 5, Pages: 1, HasPrev: true}},
+		{name: "negative", offset: -2, limit: -1, want: Page[string]{Items: []string{}, Size: 0, Offset: 0, Total: 5, HasNext: true}},
+		{name: "offset over end", offset: 9, limit: 2, want: Page[string]{Items: []string{}, Page: 5, Size: 2, Offset: 9, Total: 5, Pages: 3, HasPrev: true}},
+		{name: "offset at end", offset: 5, limit: 5, want: Page[string]{Items: []string{}, Page: 2, Size: 5, Offset: 5, Total: 5, Pages: 1, HasPrev: true}},
+		{name: "max offset", offset: math.MaxInt, limit: 1, want: Page[string]{Items: []string{}, Page: math.MaxInt, Size: 1, Offset: math.MaxInt, Total: 5, Pages: 5, HasPrev: true}},
+		{name: "max limit", offset: 4, limit: math.MaxInt, want: Page[string]{Items: []string{"e"}, Page: 1, Size: math.MaxInt, Offset: 4, Total: 5, Pages: 1, HasPrev: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OffsetLimit(items, tt.offset, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OffsetLimit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	p := OffsetLimit(items, 0, 2)
+	_ = append(p.Items, "x")
+	if items[2] != "c" {
+		t.Errorf("OffsetLimit() append overwrite source")
+	}
+}
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+	for _, key := range []string{"", "42", "user/ü?&=", "12345678"} {
+		got, err := DecodeCursor(EncodeCursor(key))
+		if err != nil || got != key {
+			t.Errorf("DecodeCursor(EncodeCursor(%q)) = %q, %v", key, got, err)
+		}
+	}
+	c := []byte(EncodeCursor("42"))
+	c[0] ^= 1
+	for _, cursor := range []string{string(c), "!!", "YWJj", EncodeCursor("42") + "A"} {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestPaginateCursor(t *testing.T) {
+	t.Parallel()
+	items := []record{{Date: 1}, {Date: 2}, {Date: 3}, {Date: 4}, {Date: 5}}
+	key := func(r record) string { return strconv.Itoa(r.Date) }
+	var got []int
+	cursor, pages := "", 0
+	for {
+		p, err := PaginateCursor(items, cursor, 2, key)
+		if err != nil {
+			t.Fatalf("PaginateCursor() error = %v", err)
+		}
+		pages++
+		got = append(got, Map(p.Items, func(r record) int { return r.Date })...)
+		if p.Total != 5 || p.HasNext != (p.Next != "") {
+			t.Errorf("PaginateCursor() = %+v", p)
+		}
+		if !p.HasNext {
+			break
+		}
+		cursor = p.Next
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) || pages != 3 {
+		t.Errorf("PaginateCursor() items = %v in %v pages", got, pages)
+	}
+	if p, err := PaginateCursor(items, EncodeCursor("5"), 2, key); err != nil || len(p.Items) != 0 || p.HasNext {
+		t.Errorf("PaginateCursor() after last = %+v, %v", p, err)
+	}
+	if _, err := PaginateCursor(items, EncodeCursor("9"), 2, key); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("PaginateCursor() missing key error = %v", err)
+	}
+	first, _ := PaginateCursor(items, "", 2, key)
+	deleted := append([]record{items[0]}, items[2:]...)
+	if _, err := PaginateCursor(deleted, first.Next, 2, key); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("PaginateCursor() deleted cursor item error = %v", err)
+	}
+	if _, err := PaginateCursor(items, "bad", 2, key); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("PaginateCursor() bad cursor error = %v", err)
+	}
+}
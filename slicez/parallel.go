@@ -0,0 +1,156 @@
+package slicez
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelOptions configure ParallelMap, ParallelFilter and ParallelForEach, zero value is GOMAXPROCS workers and first error wins.
+type ParallelOptions struct {
+	Workers   int  //Max concurrent calls of f, <= 0 is runtime.GOMAXPROCS(0)
+	AllErrors bool //Process every element and return errors.Join of all errors in index order, otherwise the first error cancel the rest
+}
+
+// IndexError is error of f at element Index, errors.Is/As see Err.
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is recovered panic of f, wrapped in IndexError.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// ParallelMap is Map with f called concurrently by opts.Workers, output keep the order of a.
+//
+// Error of f is *IndexError, panic in f is recovered as *IndexError of *PanicError. On error the output is nil.
+// ctx passed to f is canceled on the first error (unless opts.AllErrors), and elements not yet started are skipped.
+// If ctx is canceled, ctx.Err() is returned.
+func ParallelMap[T any, U any](ctx context.Context, a []T, f func(ctx context.Context, v T) (U, error), opts ParallelOptions) ([]U, error) {
+	out := make([]U, len(a))
+	err := parallel(ctx, len(a), opts, func(ctx context.Context, i int) error {
+		var err error
+		out[i], err = f(ctx, a[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ParallelFilter is Filter with keep called concurrently, output keep the order of a. See ParallelMap for errors and cancellation.
+func ParallelFilter[T any](ctx context.Context, a []T, keep func(ctx context.Context, v T) (bool, error), opts ParallelOptions) ([]T, error) {
+	kept := make([]bool, len(a))
+	err := parallel(ctx, len(a), opts, func(ctx context.Context, i int) error {
+		var err error
+		kept[i], err = keep(ctx, a[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, 0)
+	for i, v := range a {
+		if kept[i] {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// ParallelForEach call f for each element concurrently. See ParallelMap for errors and cancellation.
+func ParallelForEach[T any](ctx context.Context, a []T, f func(ctx context.Context, v T) error, opts ParallelOptions) error {
+	return parallel(ctx, len(a), opts, func(ctx context.Context, i int) error {
+		return f(ctx, a[i])
+	})
+}
+
+// parallel call f for indexes 0 to n-1 by workers
+func parallel(parent context.Context, n int, opts ParallelOptions, f func(ctx context.Context, i int) error) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	var (
+		next  atomic.Int64
+		mu    sync.Mutex
+		first error
+		errs  []*IndexError
+		wg    sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				err := callIndex(ctx, i, f)
+				if err == nil {
+					continue
+				}
+				mu.Lock()
+				if opts.AllErrors {
+					errs = append(errs, err)
+				} else if first == nil {
+					first = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if first != nil {
+		return first
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+		joined := make([]error, 0, len(errs)+1)
+		for _, err := range errs {
+			joined = append(joined, err)
+		}
+		return errors.Join(append(joined, parent.Err())...) //Join skip nil
+	}
+	return parent.Err()
+}
+
+func callIndex(ctx context.Context, i int, f func(ctx context.Context, i int) error) (err *IndexError) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &IndexError{Index: i, Err: &PanicError{Value: r, Stack: debug.Stack()}}
+		}
+	}()
+	if e := f(ctx, i); e != nil {
+		return &IndexError{Index: i, Err: e}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package slicez
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	t.Parallel()
+	a := make([]int, 100)
+	for i := range a {
+		a[i] = i
+	}
+	var running, maxRunning atomic.Int64
+	got, err := ParallelMap(context.Background(), a, func(ctx context.Context, v int) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for m := maxRunning.Load(); n > m && !maxRunning.CompareAndSwap(m, n); m = maxRunning.Load() {
+		}
+		time.Sleep(time.Duration(v%3) * time.Millisecond)
+		return strconv.Itoa(v), nil
+	}, ParallelOptions{Workers: 4})
+	if err != nil {
+		t.Fatalf("ParallelMap() error = %v", err)
+	}
+	if want := Map(a, strconv.Itoa); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelMap() = %v, want %v", got, want)
+	}
+	if m := maxRunning.Load(); m > 4 {
+		t.Errorf("ParallelMap() concurrent calls = %v, want <= 4", m)
+	}
+	if got, err := ParallelMap(context.Background(), []int(nil), func(ctx context.Context, v int) (int, error) { return v, nil }, ParallelOptions{}); err != nil || len(got) != 0 {
+		t.Errorf("ParallelMap() empty = %v, %v", got, err)
+	}
+}
+
+func TestParallelFilterForEach(t *testing.T) {
+	t.Parallel()
+	a := []int{5, 2, 8, 1, 9, 4}
+	got, err := ParallelFilter(context.Background(), a, func(ctx context.Context, v int) (bool, error) {
+		return v > 3, nil
+	}, ParallelOptions{Workers: 3})
+	if err != nil || !reflect.DeepEqual(got, []int{5, 8, 9, 4}) {
+		t.Errorf("ParallelFilter() = %v, %v", got, err)
+	}
+	var sum atomic.Int64
+	err = ParallelForEach(context.Background(), a, func(ctx context.Context, v int) error {
+		sum.Add(int64(v))
+		return nil
+	}, ParallelOptions{})
+	if err != nil || sum.Load() != 29 {
+		t.Errorf("ParallelForEach() sum = %v, %v", sum.Load(), err)
+	}
+}
+
+func TestParallelErrors(t *testing.T) {
+	t.Parallel()
+	errOdd := errors.New("odd")
+	a := make([]int, 50)
+	for i := range a {
+		a[i] = i
+	}
+	t.Run("first error wins", func(t *testing.T) {
+		var calls atomic.Int64
+		got, err := ParallelMap(context.Background(), a, func(ctx context.Context, v int) (int, error) {
+			calls.Add(1)
+			if v == 3 {
+				return 0, errOdd
+			}
+			<-ctx.Done() //Block until canceled by the error
+			return v, ctx.Err()
+		}, ParallelOptions{Workers: 4})
+		var ie *IndexError
+		if got != nil || !errors.As(err, &ie) || ie.Index != 3 || !errors.Is(err, errOdd) {
+			t.Fatalf("ParallelMap() = %v, %v", got, err)
+		}
+		if c := calls.Load(); c > 4 {
+			t.Errorf("ParallelMap() calls = %v, want stop after error", c)
+		}
+	})
+	t.Run("all errors", func(t *testing.T) {
+		err := ParallelForEach(context.Background(), a, func(ctx context.Context, v int) error {
+			if v%2 == 1 {
+				return errOdd
+			}
+			return nil
+		}, ParallelOptions{Workers: 8, AllErrors: true})
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok || len(joined.Unwrap()) != 25 {
+			t.Fatalf("ParallelForEach() error = %v", err)
+		}
+		for i, e := range joined.Unwrap() {
+			if ie := e.(*IndexError); ie.Index != 2*i+1 {
+				t.Errorf("ParallelForEach() error %d index = %v, want %v", i, ie.Index, 2*i+1)
+			}
+		}
+	})
+	t.Run("panic", func(t *testing.T) {
+		_, err := ParallelFilter(context.Background(), a, func(ctx context.Context, v int) (bool, error) {
+			if v == 7 {
+				panic("boom")
+			}
+			return true, nil
+		}, ParallelOptions{Workers: 2})
+		var ie *IndexError
+		var pe *PanicError
+		if !errors.As(err, &ie) || ie.Index != 7 || !errors.As(err, &pe) || pe.Value != "boom" || len(pe.Stack) == 0 {
+			t.Fatalf("ParallelFilter() error = %v", err)
+		}
+		if want := "element 7: panic: boom"; err.Error() != want {
+			t.Errorf("Error() = %v, want %v", err, want)
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int64
+		err := ParallelForEach(ctx, a, func(ctx context.Context, v int) error {
+			if calls.Add(1) == 5 {
+				cancel()
+			}
+			return nil
+		}, ParallelOptions{Workers: 1})
+		if !errors.Is(err, context.Canceled) || calls.Load() != 5 {
+			t.Errorf("ParallelForEach() = %v, calls %v", err, calls.Load())
+		}
+	})
+}
+
+func BenchmarkParallelMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParallelMap(context.Background(), benchInts, func(ctx context.Context, v int) (string, error) {
+			return strconv.Itoa(v), nil
+		}, ParallelOptions{})
+	}
+}
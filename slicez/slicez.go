@@ -1,6 +1,9 @@
 package slicez
 
-import "github.com/zev-zakaryan/go-util/conv"
+import (
+	"github.com/zev-zakaryan/go-util/clonez"
+	"github.com/zev-zakaryan/go-util/conv"
+)
 
 func Clone[T any](a []T) []T {
 	return append([]T(nil), a...)
@@ -14,3 +17,8 @@ func CloneCast[T any, U any](a []U) []T {
 	}
 	return b
 }
+
+// DeepClone return deep copy of a, unlike Clone nested maps, slices, pointers and structs are copied too e.g. []map[string]any. See clonez.Deep.
+func DeepClone[T any](a []T) []T {
+	return clonez.Deep(a)
+}
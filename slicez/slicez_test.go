@@ -82,3 +82,13 @@ func TestCloneCast(t *testing.T) {
 		})
 	}
 }
+
+func TestDeepClone(t *testing.T) {
+	t.Parallel()
+	a := []map[string]any{{"a": []any{1.0}}}
+	b := DeepClone(a)
+	b[0]["a"].([]any)[0] = 2.0
+	if !reflect.DeepEqual(a, []map[string]any{{"a": []any{1.0}}}) {
+		t.Errorf("DeepClone() share memory, a = %v", a)
+	}
+}
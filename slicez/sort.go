@@ -0,0 +1,232 @@
+package slicez
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zev-zakaryan/go-util/conv"
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+// Comparator return negative if a < b, 0 if equal and positive if a > b. Compose by By(...).Desc().Then(...).
+type Comparator[T any] func(a, b T) int
+
+// By compare by key in natural order of Sortable e.g. By(func(u User) string { return u.Status }).
+func By[T any, K mapz.Sortable](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return Compare(key(a), key(b))
+	}
+}
+
+// ByNatural compare string key by CompareNatural e.g. "file2" < "file10".
+func ByNatural[T any](key func(T) string) Comparator[T] {
+	return func(a, b T) int {
+		return CompareNatural(key(a), key(b))
+	}
+}
+
+// ByFold compare string key by CompareFold, case-insensitive.
+func ByFold[T any](key func(T) string) Comparator[T] {
+	return func(a, b T) int {
+		return CompareFold(key(a), key(b))
+	}
+}
+
+// ByPath compare by the first value of conv.GetItems(v, path), missing is nil and "" is v itself.
+// nil < bool < number < string < others, numbers of any type compare by value, strings by CompareNatural and others by fmt.
+func ByPath[T any](path string) Comparator[T] {
+	return func(a, b T) int {
+		return compareAny(firstItem(a, path), firstItem(b, path))
+	}
+}
+
+// Desc reverse the order.
+func (c Comparator[T]) Desc() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// Then compare by next if equal by c.
+func (c Comparator[T]) Then(next Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+		return next(a, b)
+	}
+}
+
+// SortBy sort a in place by cmp, order of equal elements is not kept. Use SortStableBy to keep.
+func SortBy[T any](a []T, cmp Comparator[T]) {
+	sort.Slice(a, func(i, j int) bool { return cmp(a[i], a[j]) < 0 })
+}
+
+// SortStableBy sort a in place by cmp, equal elements keep their order.
+func SortStableBy[T any](a []T, cmp Comparator[T]) {
+	sort.SliceStable(a, func(i, j int) bool { return cmp(a[i], a[j]) < 0 })
+}
+
+// SortByPath stable sort a in place by ByPath of paths in priority order, path with - prefix is descending e.g. SortByPath(rows, "status", "-date").
+func SortByPath(a []map[string]any, paths ...string) {
+	if len(paths) == 0 {
+		return
+	}
+	var cmp Comparator[map[string]any]
+	for _, path := range paths {
+		c := ByPath[map[string]any](strings.TrimPrefix(path, "-"))
+		if strings.HasPrefix(path, "-") {
+			c = c.Desc()
+		}
+		if cmp == nil {
+			cmp = c
+		} else {
+			cmp = cmp.Then(c)
+		}
+	}
+	SortStableBy(a, cmp)
+}
+
+// CompareNatural compare strings with digit runs by numeric value e.g. "file2" < "file10" < "file10a".
+// Numbers equal by value are ordered by fewer leading zeros, then the whole strings are compared to be total order.
+func CompareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			ei, ej := digitsEnd(a, i), digitsEnd(b, j)
+			na, nb := strings.TrimLeft(a[i:ei], "0"), strings.TrimLeft(b[j:ej], "0")
+			if len(na) != len(nb) {
+				return Compare(len(na), len(nb))
+			}
+			if r := strings.Compare(na, nb); r != 0 {
+				return r
+			}
+			if r := Compare(ei-i, ej-j); r != 0 {
+				return r
+			}
+			i, j = ei, ej
+			continue
+		}
+		ra, sa := utf8.DecodeRuneInString(a[i:])
+		rb, sb := utf8.DecodeRuneInString(b[j:])
+		if ra != rb {
+			return Compare(int(ra), int(rb))
+		}
+		i, j = i+sa, j+sb
+	}
+	if r := Compare(len(a)-i, len(b)-j); r != 0 {
+		return r
+	}
+	return strings.Compare(a, b)
+}
+
+// CompareFold compare strings case-insensitively by Unicode simple folding rune by rune, independent of locale, e.g. "apple" < "Banana" and "Go" == "GO".
+func CompareFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if fa, fb := foldRune(ra), foldRune(rb); fa != fb {
+			return Compare(int(fa), int(fb))
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return Compare(len(a), len(b))
+}
+
+// foldRune return the smallest rune of case folding orbit e.g. K for k and Kelvin sign
+func foldRune(r rune) rune {
+	out := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < out {
+			out = f
+		}
+	}
+	return out
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func digitsEnd(s string, i int) int {
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return i
+}
+
+func firstItem(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	if items := conv.GetItems(v, path); len(items) > 0 {
+		return items[0]
+	}
+	return nil
+}
+
+// compareAny compare values of any type, see ByPath
+func compareAny(a, b any) int {
+	ra, rb := rankAny(a), rankAny(b)
+	if ra != rb {
+		return Compare(ra, rb)
+	}
+	switch ra {
+	case 0:
+		return 0
+	case 1:
+		return Compare(conv.Ternary(reflect.ValueOf(a).Bool(), 1, 0), conv.Ternary(reflect.ValueOf(b).Bool(), 1, 0))
+	case 2:
+		fa, fb := toFloat(a), toFloat(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case 3:
+		return CompareNatural(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func rankAny(v any) int {
+	if v == nil {
+		return 0
+	}
+	if _, ok := v.(json.Number); ok {
+		return 2
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool:
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return 2
+	case reflect.String:
+		return 3
+	}
+	return 4
+}
+
+func toFloat(v any) float64 {
+	if n, ok := v.(json.Number); ok {
+		f, _ := n.Float64()
+		return f
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int())
+	case rv.CanUint():
+		return float64(rv.Uint())
+	}
+	return rv.Float()
+}
@@ -0,0 +1,123 @@
+package slicez
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type record struct {
+	Status string
+	Date   int
+	Name   string
+}
+
+func TestSortBy(t *testing.T) {
+	t.Parallel()
+	records := []record{
+		{"open", 3, "a"},
+		{"closed", 5, "b"},
+		{"open", 7, "c"},
+		{"closed", 5, "d"},
+		{"open", 3, "e"},
+	}
+	cmp := By(func(r record) string { return r.Status }).Then(By(func(r record) int { return r.Date }).Desc())
+	got := Clone(records)
+	SortStableBy(got, cmp)
+	want := []record{{"closed", 5, "b"}, {"closed", 5, "d"}, {"open", 7, "c"}, {"open", 3, "a"}, {"open", 3, "e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortStableBy() = %v, want %v", got, want)
+	}
+	got = Clone(records)
+	SortBy(got, cmp.Then(By(func(r record) string { return r.Name })))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy() = %v, want %v", got, want)
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	t.Parallel()
+	want := []string{"", "1", "01", "2", "10", "a", "file", "file2", "file02", "file10", "file10a", "file10b1", "file10b02", "fileA"}
+	got := Reverse(want)
+	SortBy(got, ByNatural(func(s string) string { return s }))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ByNatural() = %q, want %q", got, want)
+	}
+	for _, s := range want {
+		if CompareNatural(s, s) != 0 {
+			t.Errorf("CompareNatural(%q, %q) != 0", s, s)
+		}
+	}
+}
+
+func TestCompareFold(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"Go", "GO", 0},
+		{"apple", "Banana", -1},
+		{"Zebra", "apple", 1},
+		{"k", "K", 0}, //Kelvin sign
+		{"straße", "STRASSE", 1},
+		{"ab", "AbC", -1},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareFold(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareFold(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := CompareFold(tt.b, tt.a); got != -tt.want {
+			t.Errorf("CompareFold(%q, %q) = %v, want %v", tt.b, tt.a, got, -tt.want)
+		}
+	}
+	got := []string{"b", "C", "a", "B"}
+	SortStableBy(got, ByFold(func(s string) string { return s }))
+	if want := []string{"a", "b", "B", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ByFold() = %v, want %v", got, want)
+	}
+}
+
+func TestSortByPath(t *testing.T) {
+	t.Parallel()
+	rows := []map[string]any{
+		{"id": 1, "status": "open", "meta": map[string]any{"date": "2024-01-02"}},
+		{"id": 2, "status": "closed", "meta": map[string]any{"date": "2024-03-01"}},
+		{"id": 3, "status": "open", "meta": map[string]any{"date": "2024-05-01"}},
+		{"id": 4, "meta": map[string]any{"date": "2023-01-01"}},
+		{"id": 5, "status": "closed", "meta": map[string]any{"date": "2024-03-01"}},
+	}
+	SortByPath(rows, "status", "-meta.date")
+	if got, want := Map(rows, func(m map[string]any) any { return m["id"] }), []any{4, 2, 5, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByPath() ids = %v, want %v", got, want)
+	}
+
+	values := []any{"b", json.Number("2.5"), nil, true, 3, "a10", 1.5, false, "a9", []int{1}, uint8(2)}
+	SortStableBy(values, ByPath[any](""))
+	want := []any{nil, false, true, 1.5, uint8(2), json.Number("2.5"), 3, "a9", "a10", "b", []int{1}}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("ByPath() = %v, want %v", values, want)
+	}
+}
+
+func BenchmarkSortBy(b *testing.B) {
+	cmp := By(func(v int) int { return v % 7 }).Then(By(func(v int) int { return v }).Desc())
+	for i := 0; i < b.N; i++ {
+		a := Clone(benchInts)
+		SortBy(a, cmp)
+	}
+}
+
+func BenchmarkSortSlice(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		a := Clone(benchInts)
+		sort.Slice(a, func(i, j int) bool {
+			if a[i]%7 != a[j]%7 {
+				return a[i]%7 < a[j]%7
+			}
+			return a[i] > a[j]
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package slicez
+
+import (
+	"container/heap"
+
+	"github.com/zev-zakaryan/go-util/mapz"
+)
+
+// Compare is natural order of Sortable as Comparator e.g. LowerBound(ids, 42, Compare[int]).
+func Compare[T mapz.Sortable](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// LowerBound return the first index of a sorted by cmp that is not less than target, len(a) if none.
+func LowerBound[T any](a []T, target T, cmp Comparator[T]) int {
+	lo, hi := 0, len(a)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if cmp(a[mid], target) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound return the first index of a sorted by cmp that is greater than target, len(a) if none.
+// a[LowerBound:UpperBound] are all elements equal to target.
+func UpperBound[T any](a []T, target T, cmp Comparator[T]) int {
+	lo, hi := 0, len(a)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if cmp(a[mid], target) <= 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// BinarySearchBy return index of the first element equal to target in a sorted by cmp and true, or index to insert target and false.
+func BinarySearchBy[T any](a []T, target T, cmp Comparator[T]) (int, bool) {
+	i := LowerBound(a, target, cmp)
+	return i, i < len(a) && cmp(a[i], target) == 0
+}
+
+// InsertSorted insert v into a sorted by cmp after equal elements and return the result, a may be modified as append.
+func InsertSorted[T any](a []T, v T, cmp Comparator[T]) []T {
+	i := UpperBound(a, v, cmp)
+	var zero T
+	a = append(a, zero)
+	copy(a[i+1:], a[i:])
+	a[i] = v
+	return a
+}
+
+// RemoveSorted remove the first element equal to v from a sorted by cmp and return the result and true, or a and false if not found. a is modified.
+func RemoveSorted[T any](a []T, v T, cmp Comparator[T]) ([]T, bool) {
+	i, found := BinarySearchBy(a, v, cmp)
+	if !found {
+		return a, false
+	}
+	copy(a[i:], a[i+1:])
+	var zero T
+	a[len(a)-1] = zero //Release reference for gc
+	return a[:len(a)-1], true
+}
+
+// MergeSorted merge slices each sorted by cmp into a new sorted slice in O(n log k).
+// Equal elements keep the order of slices, so it is stable.
+func MergeSorted[T any](cmp Comparator[T], slices ...[]T) []T {
+	n := 0
+	h := &mergeHeap[T]{cmp: cmp}
+	for i, s := range slices {
+		n += len(s)
+		if len(s) > 0 {
+			h.items = append(h.items, mergeItem{slice: i})
+		}
+	}
+	h.slices = slices
+	heap.Init(h)
+	out := make([]T, 0, n)
+	for h.Len() > 0 {
+		top := &h.items[0]
+		out = append(out, slices[top.slice][top.index])
+		top.index++
+		if top.index == len(slices[top.slice]) {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return out
+}
+
+type mergeItem struct {
+	slice int
+	index int
+}
+
+// mergeHeap is heap of the next element of each slice
+type mergeHeap[T any] struct {
+	cmp    Comparator[T]
+	slices [][]T
+	items  []mergeItem
+}
+
+func (h *mergeHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if r := h.cmp(h.slices[a.slice][a.index], h.slices[b.slice][b.index]); r != 0 {
+		return r < 0
+	}
+	return a.slice < b.slice
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *mergeHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(mergeItem))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,112 @@
+package slicez
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBounds(t *testing.T) {
+	t.Parallel()
+	a := []int{1, 2, 2, 2, 5, 7}
+	tests := []struct {
+		target     int
+		lower      int
+		upper      int
+		wantFound  bool
+		wantSearch int
+	}{
+		{target: 0, lower: 0, upper: 0, wantSearch: 0},
+		{target: 1, lower: 0, upper: 1, wantFound: true, wantSearch: 0},
+		{target: 2, lower: 1, upper: 4, wantFound: true, wantSearch: 1},
+		{target: 3, lower: 4, upper: 4, wantSearch: 4},
+		{target: 7, lower: 5, upper: 6, wantFound: true, wantSearch: 5},
+		{target: 8, lower: 6, upper: 6, wantSearch: 6},
+	}
+	for _, tt := range tests {
+		if got := LowerBound(a, tt.target, Compare[int]); got != tt.lower {
+			t.Errorf("LowerBound(%v) = %v, want %v", tt.target, got, tt.lower)
+		}
+		if got := UpperBound(a, tt.target, Compare[int]); got != tt.upper {
+			t.Errorf("UpperBound(%v) = %v, want %v", tt.target, got, tt.upper)
+		}
+		if got, found := BinarySearchBy(a, tt.target, Compare[int]); got != tt.wantSearch || found != tt.wantFound {
+			t.Errorf("BinarySearchBy(%v) = %v, %v, want %v, %v", tt.target, got, found, tt.wantSearch, tt.wantFound)
+		}
+	}
+	if i, found := BinarySearchBy(nil, 1, Compare[int]); i != 0 || found {
+		t.Errorf("BinarySearchBy() empty = %v, %v", i, found)
+	}
+	desc := []record{{Date: 9}, {Date: 5}, {Date: 5}, {Date: 1}}
+	byDateDesc := By(func(r record) int { return r.Date }).Desc()
+	if got := LowerBound(desc, record{Date: 5}, byDateDesc); got != 1 {
+		t.Errorf("LowerBound() desc = %v, want 1", got)
+	}
+}
+
+func TestInsertRemoveSorted(t *testing.T) {
+	t.Parallel()
+	byDate := By(func(r record) int { return r.Date })
+	var a []record
+	for i, d := range []int{5, 1, 5, 9, 1} {
+		a = InsertSorted(a, record{Date: d, Name: string(rune('a' + i))}, byDate)
+	}
+	want := []record{{Date: 1, Name: "b"}, {Date: 1, Name: "e"}, {Date: 5, Name: "a"}, {Date: 5, Name: "c"}, {Date: 9, Name: "d"}}
+	if !reflect.DeepEqual(a, want) {
+		t.Fatalf("InsertSorted() = %v, want %v", a, want)
+	}
+	a, ok := RemoveSorted(a, record{Date: 5}, byDate)
+	if want := []record{{Date: 1, Name: "b"}, {Date: 1, Name: "e"}, {Date: 5, Name: "c"}, {Date: 9, Name: "d"}}; !ok || !reflect.DeepEqual(a, want) {
+		t.Errorf("RemoveSorted() = %v, %v, want %v", a, ok, want)
+	}
+	if got, ok := RemoveSorted(a, record{Date: 3}, byDate); ok || len(got) != 4 {
+		t.Errorf("RemoveSorted() missing = %v, %v", got, ok)
+	}
+	if got, ok := RemoveSorted([]int{}, 1, Compare[int]); ok || len(got) != 0 {
+		t.Errorf("RemoveSorted() empty = %v, %v", got, ok)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	t.Parallel()
+	byDate := By(func(r record) int { return r.Date })
+	got := MergeSorted(byDate,
+		[]record{{Date: 1, Name: "a0"}, {Date: 3, Name: "a1"}, {Date: 3, Name: "a2"}},
+		nil,
+		[]record{{Date: 2, Name: "c0"}, {Date: 3, Name: "c1"}},
+		[]record{{Date: 0, Name: "d0"}, {Date: 3, Name: "d1"}, {Date: 9, Name: "d2"}},
+	)
+	want := []record{{Date: 0, Name: "d0"}, {Date: 1, Name: "a0"}, {Date: 2, Name: "c0"}, {Date: 3, Name: "a1"}, {Date: 3, Name: "a2"}, {Date: 3, Name: "c1"}, {Date: 3, Name: "d1"}, {Date: 9, Name: "d2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeSorted() = %v, want %v", got, want)
+	}
+	if got := MergeSorted[int](Compare[int]); len(got) != 0 {
+		t.Errorf("MergeSorted() none = %v", got)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	var slices [][]int
+	var all []int
+	for i := 0; i < 20; i++ {
+		s := make([]int, rng.Intn(50))
+		for j := range s {
+			s[j] = rng.Intn(100)
+		}
+		sort.Ints(s)
+		slices = append(slices, s)
+		all = append(all, s...)
+	}
+	sort.Ints(all)
+	if got := MergeSorted(Compare[int], slices...); !reflect.DeepEqual(got, all) {
+		t.Errorf("MergeSorted() random = %v, want %v", got, all)
+	}
+}
+
+func BenchmarkBinarySearchBy(b *testing.B) {
+	a := Clone(benchInts)
+	sort.Ints(a)
+	for i := 0; i < b.N; i++ {
+		_, _ = BinarySearchBy(a, i%1000, Compare[int])
+	}
+}
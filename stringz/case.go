@@ -0,0 +1,153 @@
+package stringz
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GoInitialisms is common initialisms of Go naming convention, use with NewCaser e.g. NewCaser(GoInitialisms...).Pascal("user_id") is UserID.
+var GoInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC",
+	"SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+var defaultCaser = NewCaser()
+
+// Caser convert between cases with configured initialisms. Initialism keeps its given form in Camel (except first word), Pascal and Title.
+type Caser struct {
+	initialisms map[string]string //lowercase to given form
+}
+
+func NewCaser(initialisms ...string) *Caser {
+	c := &Caser{initialisms: make(map[string]string, len(initialisms))}
+	for _, in := range initialisms {
+		c.initialisms[strings.ToLower(in)] = in
+	}
+	return c
+}
+
+// Words split s to words by any non letter or digit, lower to upper case change and end of acronym e.g. HTTPServer is HTTP, Server.
+//
+// Digits belong to the preceding word e.g. utf8Reader is utf8, Reader and v2 is v2.
+// Acronym with plural s is one word e.g. IDsByURLs is IDs, By, URLs.
+func Words(s string) []string {
+	var out []string
+	rs := []rune(s)
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				out = append(out, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			if !unicode.IsUpper(prev) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]) && !isPluralS(rs, i+1)) {
+				out = append(out, string(rs[start:i]))
+				start = -1
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		out = append(out, string(rs[start:]))
+	}
+	return out
+}
+
+// isPluralS check that rs[i] is lowercase s at the end of word
+func isPluralS(rs []rune, i int) bool {
+	return rs[i] == 's' && (i+1 == len(rs) || !unicode.IsLower(rs[i+1]))
+}
+
+func (c *Caser) Snake(s string) string {
+	return c.join(s, "_", strings.ToLower)
+}
+
+func (c *Caser) ScreamingSnake(s string) string {
+	return c.join(s, "_", strings.ToUpper)
+}
+
+func (c *Caser) Kebab(s string) string {
+	return c.join(s, "-", strings.ToLower)
+}
+
+func (c *Caser) Dot(s string) string {
+	return c.join(s, ".", strings.ToLower)
+}
+
+func (c *Caser) Pascal(s string) string {
+	return c.join(s, "", c.title)
+}
+
+func (c *Caser) Title(s string) string {
+	return c.join(s, " ", c.title)
+}
+
+func (c *Caser) Camel(s string) string {
+	first := true
+	return c.join(s, "", func(w string) string {
+		if first {
+			first = false
+			return strings.ToLower(w)
+		}
+		return c.title(w)
+	})
+}
+
+func (c *Caser) join(s, sep string, f func(string) string) string {
+	words := Words(s)
+	for i, w := range words {
+		words[i] = f(w)
+	}
+	return strings.Join(words, sep)
+}
+
+func (c *Caser) title(w string) string {
+	lower := strings.ToLower(w)
+	if in, ok := c.initialisms[lower]; ok {
+		return in
+	}
+	rs := []rune(lower)
+	rs[0] = unicode.ToTitle(rs[0])
+	return string(rs)
+}
+
+// ToSnake e.g. HTTPServer, http-server and Http Server are http_server.
+func ToSnake(s string) string {
+	return defaultCaser.Snake(s)
+}
+
+// ToScreamingSnake e.g. httpServer is HTTP_SERVER.
+func ToScreamingSnake(s string) string {
+	return defaultCaser.ScreamingSnake(s)
+}
+
+// ToKebab e.g. HTTPServer is http-server.
+func ToKebab(s string) string {
+	return defaultCaser.Kebab(s)
+}
+
+// ToDot e.g. HTTPServer is http.server.
+func ToDot(s string) string {
+	return defaultCaser.Dot(s)
+}
+
+// ToPascal e.g. http_server is HttpServer, use NewCaser(GoInitialisms...) for HTTPServer.
+func ToPascal(s string) string {
+	return defaultCaser.Pascal(s)
+}
+
+// ToCamel e.g. http_server is httpServer.
+func ToCamel(s string) string {
+	return defaultCaser.Camel(s)
+}
+
+// ToTitle e.g. httpServer is Http Server.
+func ToTitle(s string) string {
+	return defaultCaser.Title(s)
+}
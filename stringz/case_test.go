@@ -0,0 +1,97 @@
+package stringz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{s: "", want: nil},
+		{s: "__", want: nil},
+		{s: "HTTPServer", want: []string{"HTTP", "Server"}},
+		{s: "userIDToken", want: []string{"user", "ID", "Token"}},
+		{s: "utf8Reader v2 HTTP2Server", want: []string{"utf8", "Reader", "v2", "HTTP2", "Server"}},
+		{s: "snake_case-kebab.dot Title", want: []string{"snake", "case", "kebab", "dot", "Title"}},
+		{s: "ÉtéÀParis", want: []string{"Été", "À", "Paris"}},
+		{s: "größeDatei", want: []string{"größe", "Datei"}},
+		{s: "日本語_テキスト", want: []string{"日本語", "テキスト"}},
+		{s: "IDsByURLs", want: []string{"IDs", "By", "URLs"}},
+		{s: "HTTPSend", want: []string{"HTTP", "Send"}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.s); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s                                                  string
+		snake, screaming, kebab, dot, pascal, camel, title string
+	}{
+		{
+			s: "HTTPServer", snake: "http_server", screaming: "HTTP_SERVER", kebab: "http-server", dot: "http.server",
+			pascal: "HttpServer", camel: "httpServer", title: "Http Server",
+		},
+		{
+			s: "  user_id__v2 ", snake: "user_id_v2", screaming: "USER_ID_V2", kebab: "user-id-v2", dot: "user.id.v2",
+			pascal: "UserIdV2", camel: "userIdV2", title: "User Id V2",
+		},
+		{
+			s: "ÉTÉ à-paris", snake: "été_à_paris", screaming: "ÉTÉ_À_PARIS", kebab: "été-à-paris", dot: "été.à.paris",
+			pascal: "ÉtéÀParis", camel: "étéÀParis", title: "Été À Paris",
+		},
+		{s: ""},
+	}
+	for _, tt := range tests {
+		for name, got := range map[string][2]string{
+			"ToSnake":          {ToSnake(tt.s), tt.snake},
+			"ToScreamingSnake": {ToScreamingSnake(tt.s), tt.screaming},
+			"ToKebab":          {ToKebab(tt.s), tt.kebab},
+			"ToDot":            {ToDot(tt.s), tt.dot},
+			"ToPascal":         {ToPascal(tt.s), tt.pascal},
+			"ToCamel":          {ToCamel(tt.s), tt.camel},
+			"ToTitle":          {ToTitle(tt.s), tt.title},
+		} {
+			if got[0] != got[1] {
+				t.Errorf("%v(%q) = %q, want %q", name, tt.s, got[0], got[1])
+			}
+		}
+	}
+}
+
+func TestCaserInitialisms(t *testing.T) {
+	t.Parallel()
+	c := NewCaser(append(GoInitialisms, "OAuth")...)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "pascal", got: c.Pascal("user_id_url"), want: "UserIDURL"},
+		{name: "pascal acronym", got: c.Pascal("HTTPServer"), want: "HTTPServer"},
+		{name: "camel first word", got: c.Camel("id_token"), want: "idToken"},
+		{name: "camel", got: c.Camel("parse-json-api"), want: "parseJSONAPI"},
+		{name: "custom form", got: c.Title("oauth_callback"), want: "OAuth Callback"},
+		{name: "snake", got: c.Snake("UserIDURL"), want: "user_idurl"},
+		{name: "snake separated", got: c.Snake("UserID_URL"), want: "user_id_url"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%v = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func BenchmarkSnake2Title(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Snake2Title("i_love_golang")
+	}
+}
@@ -0,0 +1,234 @@
+package stringz
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// IndexOption configure IndexOfNth, LastIndexOfNth and AllIndexes.
+type IndexOption string
+
+const (
+	OptOverlap       IndexOption = "overlap"        //Next match can start inside previous match e.g. "aa" in "aaa" is at 0 and 1
+	OptIgnoreCase    IndexOption = "ignore case"    //Unicode simple case folding as strings.EqualFold
+	OptRuneIndex     IndexOption = "rune index"     //Index is count of runes instead of bytes
+	OptGraphemeIndex IndexOption = "grapheme index" //Index is count of grapheme clusters, match must start and end at cluster boundary
+)
+
+// LastIndexOfNth is IndexOfNth searching from the end e.g. nth 1 is the last match. Return len(s) in the index unit if substr is empty.
+//
+// Without OptOverlap, matches are counted from the end so "aa" in "aaa" is at 1 only.
+func LastIndexOfNth(s string, substr string, nth int, opts ...IndexOption) int {
+	if nth < 1 {
+		nth = 1
+	}
+	o := newSearchOptions(opts)
+	bounds := o.boundaries(s)
+	if substr == "" {
+		return o.index(bounds, len(bounds)-1)
+	}
+	limit := len(s)
+	for bi := len(bounds) - 2; bi >= 0; bi-- {
+		start := bounds[bi]
+		end, ok := o.match(s, start, substr, bounds)
+		if !ok || end > limit {
+			continue
+		}
+		if nth--; nth == 0 {
+			return o.index(bounds, bi)
+		}
+		if !o.overlap {
+			limit = start
+		}
+	}
+	return -1
+}
+
+// AllIndexes return iterator of all match indexes of substr from the start, stop when yield return false.
+//
+// Index is byte offset unless OptRuneIndex or OptGraphemeIndex. Nothing is yielded if substr is empty.
+//
+//	AllIndexes("ÀBàbÀ", "à", OptIgnoreCase, OptRuneIndex)(func(i int) bool {
+//		fmt.Println(i) //0, 2, 4
+//		return true
+//	})
+func AllIndexes(s string, substr string, opts ...IndexOption) func(yield func(index int) bool) {
+	return func(yield func(int) bool) {
+		if substr == "" {
+			return
+		}
+		o := newSearchOptions(opts)
+		bounds := o.boundaries(s)
+		limit := 0
+		for bi := 0; bi < len(bounds)-1; bi++ {
+			start := bounds[bi]
+			if start < limit {
+				continue
+			}
+			end, ok := o.match(s, start, substr, bounds)
+			if !ok {
+				continue
+			}
+			if !yield(o.index(bounds, bi)) {
+				return
+			}
+			if !o.overlap {
+				limit = end
+			}
+		}
+	}
+}
+
+// Graphemes split s to grapheme clusters (user-perceived characters) e.g. Thai "กำ" and emoji "👍🏽" are one cluster each.
+//
+// This is simplified UAX #29: combining and spacing marks, ZWJ emoji sequences, emoji modifiers, flags and CR LF are supported,
+// but Hangul jamo sequences (precomposed syllables are fine) and Indic conjuncts are not joined.
+func Graphemes(s string) []string {
+	bounds := graphemeBoundaries(s)
+	out := make([]string, len(bounds)-1)
+	for i := range out {
+		out[i] = s[bounds[i]:bounds[i+1]]
+	}
+	return out
+}
+
+type searchOptions struct {
+	overlap    bool
+	ignoreCase bool
+	runeIndex  bool
+	grapheme   bool
+}
+
+func newSearchOptions(opts []IndexOption) searchOptions {
+	var o searchOptions
+	for _, opt := range opts {
+		switch opt {
+		case OptOverlap:
+			o.overlap = true
+		case OptIgnoreCase:
+			o.ignoreCase = true
+		case OptRuneIndex:
+			o.runeIndex = true
+		case OptGraphemeIndex:
+			o.grapheme = true
+		}
+	}
+	return o
+}
+
+// boundaries return byte offset of each unit (rune or grapheme) start and len(s) as the last item
+func (o searchOptions) boundaries(s string) []int {
+	if o.grapheme {
+		return graphemeBoundaries(s)
+	}
+	out := make([]int, 0, len(s)+1)
+	for i := range s {
+		out = append(out, i)
+	}
+	return append(out, len(s))
+}
+
+// index return index of bounds[bi] in the unit of options
+func (o searchOptions) index(bounds []int, bi int) int {
+	if o.runeIndex || o.grapheme {
+		return bi
+	}
+	return bounds[bi]
+}
+
+// match check substr at s[start:], return end byte offset of match
+func (o searchOptions) match(s string, start int, substr string, bounds []int) (int, bool) {
+	end := start + len(substr)
+	if o.ignoreCase {
+		var ok bool
+		if end, ok = matchFold(s, start, substr); !ok {
+			return 0, false
+		}
+	} else if !strings.HasPrefix(s[start:], substr) {
+		return 0, false
+	}
+	if o.grapheme {
+		if i := sort.SearchInts(bounds, end); i == len(bounds) || bounds[i] != end {
+			return 0, false
+		}
+	}
+	return end, true
+}
+
+// matchFold is strings.HasPrefix with simple case folding, return end byte offset because folded runes may have different size
+func matchFold(s string, start int, substr string) (int, bool) {
+	i := start
+	for _, sr := range substr {
+		if i >= len(s) {
+			return 0, false
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r != sr && !equalFoldRune(r, sr) {
+			return 0, false
+		}
+		i += size
+	}
+	return i, true
+}
+
+func equalFoldRune(a, b rune) bool {
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+func graphemeBoundaries(s string) []int {
+	out := make([]int, 0, len(s)+1)
+	prev := rune(-1)
+	regional := 0 //Count of consecutive regional indicators ending at prev
+	for i, r := range s {
+		if prev < 0 || !graphemeJoin(prev, r, regional) {
+			out = append(out, i)
+		}
+		if isRegionalIndicator(r) {
+			regional++
+		} else {
+			regional = 0
+		}
+		prev = r
+	}
+	return append(out, len(s))
+}
+
+// graphemeJoin check that there is no cluster boundary between prev and r
+func graphemeJoin(prev, r rune, regional int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case unicode.IsControl(prev) || unicode.IsControl(r):
+		return false
+	case isGraphemeExtend(r):
+		return true
+	case prev == '\u200d' && isPictographic(r):
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return regional%2 == 1
+	}
+	return false
+}
+
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == '\u200d' || //ZWJ
+		r == '\u0e33' || r == '\u0eb3' || //Thai and Lao SARA AM are spacing marks
+		(r >= 0x1f3fb && r <= 0x1f3ff) || //Emoji skin tone modifiers
+		(r >= 0xe0020 && r <= 0xe007f) //Tags of subdivision flags
+}
+
+func isPictographic(r rune) bool {
+	return unicode.Is(unicode.So, r) || (r >= 0x1f000 && r <= 0x1faff)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,93 @@
+package stringz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndexOfNthOptions(t *testing.T) {
+	t.Parallel()
+	const thai = "กำลังกินกำไร" //กำ is one grapheme of 2 runes, graphemes are กำ ลั ง กิ น กำ ไ ร
+	const emoji = "👍🏽a👍🏽b👍"
+	tests := []struct {
+		name   string
+		s      string
+		substr string
+		nth    int
+		opts   []IndexOption
+		want   int
+		last   int
+	}{
+		{name: "byte", s: "aXbXc", substr: "X", nth: 2, want: 3, last: 1},
+		{name: "overlap", s: "aaaa", substr: "aa", nth: 2, opts: []IndexOption{OptOverlap}, want: 1, last: 1},
+		{name: "no overlap", s: "aaaa", substr: "aa", nth: 2, want: 2, last: 0},
+		{name: "no overlap from end", s: "aaa", substr: "aa", nth: 1, want: 0, last: 1},
+		{name: "ignore case", s: "Go GO go", substr: "go", nth: 2, opts: []IndexOption{OptIgnoreCase}, want: 3, last: 3},
+		{name: "ignore case unicode size", s: "\u212a-k", substr: "k", nth: 1, opts: []IndexOption{OptIgnoreCase}, want: 0, last: 4},
+		{name: "rune", s: thai, substr: "กำ", nth: 2, opts: []IndexOption{OptRuneIndex}, want: 8, last: 0},
+		{name: "grapheme", s: thai, substr: "กำ", nth: 2, opts: []IndexOption{OptGraphemeIndex}, want: 5, last: 0},
+		{name: "grapheme not split cluster", s: thai, substr: "ก", nth: 1, opts: []IndexOption{OptGraphemeIndex}, want: -1, last: -1},
+		{name: "rune split cluster", s: thai, substr: "ก", nth: 1, opts: []IndexOption{OptRuneIndex}, want: 0, last: 8},
+		{name: "emoji grapheme", s: emoji, substr: "b", nth: 1, opts: []IndexOption{OptGraphemeIndex}, want: 3, last: 3},
+		{name: "emoji without modifier", s: emoji, substr: "👍", nth: 1, opts: []IndexOption{OptGraphemeIndex}, want: 4, last: 4},
+		{name: "emoji rune", s: emoji, substr: "b", nth: 1, opts: []IndexOption{OptRuneIndex}, want: 5, last: 5},
+		{name: "not found", s: "abc", substr: "x", nth: 1, opts: []IndexOption{OptRuneIndex}, want: -1, last: -1},
+		{name: "nth too large", s: "abc", substr: "b", nth: 2, opts: []IndexOption{OptRuneIndex}, want: -1, last: -1},
+		{name: "empty", s: thai, substr: "", nth: 1, opts: []IndexOption{OptGraphemeIndex}, want: 0, last: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IndexOfNth(tt.s, tt.substr, tt.nth, tt.opts...); got != tt.want {
+				t.Errorf("IndexOfNth() = %v, want %v", got, tt.want)
+			}
+			if got := LastIndexOfNth(tt.s, tt.substr, tt.nth, tt.opts...); got != tt.last {
+				t.Errorf("LastIndexOfNth() = %v, want %v", got, tt.last)
+			}
+		})
+	}
+}
+
+func TestAllIndexes(t *testing.T) {
+	t.Parallel()
+	collect := func(s, substr string, max int, opts ...IndexOption) []int {
+		var out []int
+		AllIndexes(s, substr, opts...)(func(i int) bool {
+			out = append(out, i)
+			return len(out) < max
+		})
+		return out
+	}
+	if got, want := collect("ÀBàbÀ", "à", 10, OptIgnoreCase, OptRuneIndex), []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllIndexes() = %v, want %v", got, want)
+	}
+	if got, want := collect("abababa", "aba", 10, OptOverlap), []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllIndexes() overlap = %v, want %v", got, want)
+	}
+	if got, want := collect("abababa", "aba", 2, OptOverlap), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllIndexes() stop = %v, want %v", got, want)
+	}
+	if got := collect("abc", "", 10); got != nil {
+		t.Errorf("AllIndexes() empty = %v", got)
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{s: "", want: []string{}},
+		{s: "กำลัง", want: []string{"กำ", "ลั", "ง"}},
+		{s: "e\u0301x", want: []string{"e\u0301", "x"}},
+		{s: "👨\u200d👩\u200d👧👍🏽", want: []string{"👨\u200d👩\u200d👧", "👍🏽"}},
+		{s: "🇹🇭🇯🇵🇺", want: []string{"🇹🇭", "🇯🇵", "🇺"}},
+		{s: "a\r\n\nb", want: []string{"a", "\r\n", "\n", "b"}},
+		{s: "\u0301a", want: []string{"\u0301", "a"}},
+	}
+	for _, tt := range tests {
+		if got := Graphemes(tt.s); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Graphemes(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
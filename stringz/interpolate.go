@@ -0,0 +1,289 @@
+package stringz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+// InterpolateOption configure Interpolate.
+type InterpolateOption string
+
+const (
+	OptKeepUnresolved InterpolateOption = "keep unresolved" //Leave unresolved placeholder as is instead of error
+	OptEscapeHtml     InterpolateOption = "escape html"     //Escape value for html text and attribute
+	OptEscapeUrl      InterpolateOption = "escape url"      //Escape value for url query
+	OptEscapeJson     InterpolateOption = "escape json"     //Escape value for inside json string (without quotes)
+)
+
+// ErrUnresolved is wrapped by Interpolate error when placeholder path has no value.
+var ErrUnresolved = errors.New("unresolved placeholder")
+
+type filterFunc func(v any, ok bool, arg string) (any, bool, error)
+
+var filters = map[string]filterFunc{
+	"upper":       stringFilter(strings.ToUpper),
+	"lower":       stringFilter(strings.ToLower),
+	"trim":        stringFilter(strings.TrimSpace),
+	"snake2title": stringFilter(Snake2Title),
+	"default": func(v any, ok bool, arg string) (any, bool, error) {
+		if !ok || v == nil || v == "" {
+			return arg, true, nil
+		}
+		return v, ok, nil
+	},
+	"json": func(v any, ok bool, arg string) (any, bool, error) {
+		return ToJson(v, arg), ok, nil
+	},
+	"join": func(v any, ok bool, arg string) (any, bool, error) {
+		if !ok {
+			return v, ok, nil
+		}
+		if arg == "" {
+			arg = ", "
+		}
+		items := toList(v)
+		ss := make([]string, len(items))
+		for i, item := range items {
+			ss[i] = toString(item)
+		}
+		return strings.Join(ss, arg), true, nil
+	},
+	"sum": func(v any, ok bool, arg string) (any, bool, error) {
+		if !ok {
+			return v, ok, nil
+		}
+		sum := 0.0
+		for _, item := range toList(v) {
+			f, err := conv.To[float64](item)
+			if err != nil {
+				return nil, false, fmt.Errorf("sum of non number %v", item)
+			}
+			sum += f
+		}
+		return sum, true, nil
+	},
+	"count": func(v any, ok bool, arg string) (any, bool, error) {
+		if !ok {
+			return 0, true, nil
+		}
+		if s, isString := v.(string); isString {
+			return utf8.RuneCountInString(s), true, nil
+		}
+		return len(toList(v)), true, nil
+	},
+	"first": func(v any, ok bool, arg string) (any, bool, error) {
+		if items := toList(v); ok && len(items) > 0 {
+			return items[0], true, nil
+		}
+		return nil, false, nil
+	},
+	"last": func(v any, ok bool, arg string) (any, bool, error) {
+		if items := toList(v); ok && len(items) > 0 {
+			return items[len(items)-1], true, nil
+		}
+		return nil, false, nil
+	},
+}
+
+func stringFilter(f func(string) string) filterFunc {
+	return func(v any, ok bool, arg string) (any, bool, error) {
+		if !ok {
+			return v, ok, nil
+		}
+		return f(toString(v)), true, nil
+	}
+}
+
+// toList return items of slice or map (sorted by key as conv.GetItems), other value is list of itself
+func toList(v any) []any {
+	if l, ok := v.([]any); ok {
+		return l
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Map:
+		return conv.GetItems(v, "#")
+	}
+	return []any{v}
+}
+
+func toString(v any) string {
+	if f, ok := v.(float64); ok { //Avoid exponent format of %v e.g. 1e+06
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return conv.ToForce[string](v)
+}
+
+// Interpolate render {{path|filter|filter:arg}} placeholders of tmpl with values from data.
+//
+// path is conv.GetItems syntax e.g. order.items.#.price, path with # or ^ is list of all matched values.
+// null is the same as missing value. Map and slice are rendered as json.
+//
+// Filters: upper, lower, trim, snake2title, default:value, json[:indent], join[:sep] (default ", "), sum, count, first, last.
+// Arg can be quoted by " or ' to contain | or }}. Use \{{ for literal {{.
+//
+// Unresolved placeholder (no value after filters) is error wrapped ErrUnresolved, or kept as is with OptKeepUnresolved.
+// OptEscapeHtml, OptEscapeUrl and OptEscapeJson escape rendered values, not the template text.
+func Interpolate(tmpl string, data any, opts ...InterpolateOption) (string, error) {
+	optsMap := make(map[InterpolateOption]struct{}, len(opts))
+	for _, opt := range opts {
+		optsMap[opt] = struct{}{}
+	}
+	var sb strings.Builder
+	var unresolved []string
+	for {
+		start := strings.Index(tmpl, "{{")
+		if start == -1 {
+			break
+		}
+		if start > 0 && tmpl[start-1] == '\\' {
+			sb.WriteString(tmpl[:start-1] + "{{")
+			tmpl = tmpl[start+2:]
+			continue
+		}
+		end := placeholderEnd(tmpl[start+2:])
+		if end == -1 {
+			return "", fmt.Errorf("unclosed placeholder at %q", tmpl[start:])
+		}
+		sb.WriteString(tmpl[:start])
+		placeholder := tmpl[start : start+2+end+2]
+		v, ok, err := evaluate(tmpl[start+2:start+2+end], data)
+		if err != nil {
+			return "", fmt.Errorf("fail render %v: %w", placeholder, err)
+		}
+		if !ok {
+			if _, keep := optsMap[OptKeepUnresolved]; !keep {
+				unresolved = append(unresolved, placeholder)
+			}
+			sb.WriteString(placeholder)
+		} else {
+			sb.WriteString(escape(toString(v), optsMap))
+		}
+		tmpl = tmpl[start+2+end+2:]
+	}
+	sb.WriteString(tmpl)
+	if len(unresolved) > 0 {
+		return "", fmt.Errorf("%w: %v", ErrUnresolved, strings.Join(unresolved, ", "))
+	}
+	return sb.String(), nil
+}
+
+// placeholderEnd return index of closing }} outside quotes, -1 if not found
+func placeholderEnd(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == '\\' {
+				i++
+			} else if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case strings.HasPrefix(s[i:], "}}"):
+			return i
+		}
+	}
+	return -1
+}
+
+func evaluate(expr string, data any) (v any, ok bool, err error) {
+	parts := splitPipe(expr)
+	path := strings.TrimSpace(parts[0])
+	if path == "" {
+		return nil, false, errors.New("empty path")
+	}
+	items := conv.GetItems(data, path, conv.OptOmitNoValue)
+	if isMultiPath(path) {
+		v, ok = items, true
+	} else if len(items) > 0 {
+		v, ok = items[0], true
+	}
+	for _, part := range parts[1:] {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), ":")
+		name = strings.TrimSpace(name)
+		f, exists := filters[name]
+		if !exists {
+			return nil, false, fmt.Errorf("unknown filter %q", name)
+		}
+		if arg, err = unquoteArg(strings.TrimSpace(arg)); err != nil {
+			return nil, false, fmt.Errorf("invalid arg of filter %v: %w", name, err)
+		}
+		if v, ok, err = f(v, ok, arg); err != nil {
+			return nil, false, fmt.Errorf("filter %v: %w", name, err)
+		}
+	}
+	return v, ok, nil
+}
+
+func isMultiPath(path string) bool {
+	for _, key := range strings.Split(path, ".") {
+		if key == "#" || key == "#v" || key == "#k" || strings.HasPrefix(key, "^") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPipe split by | outside quotes
+func splitPipe(expr string) []string {
+	var out []string
+	var quote byte
+	last := 0
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case quote != 0:
+			if expr[i] == '\\' {
+				i++
+			} else if expr[i] == quote {
+				quote = 0
+			}
+		case expr[i] == '"' || expr[i] == '\'':
+			quote = expr[i]
+		case expr[i] == '|':
+			out = append(out, expr[last:i])
+			last = i + 1
+		}
+	}
+	return append(out, expr[last:])
+}
+
+func unquoteArg(arg string) (string, error) {
+	if len(arg) < 2 || (arg[0] != '"' && arg[0] != '\'') {
+		return arg, nil
+	}
+	if arg[0] == '\'' {
+		if arg[len(arg)-1] != '\'' {
+			return "", fmt.Errorf("unclosed quote %v", arg)
+		}
+		return strings.ReplaceAll(arg[1:len(arg)-1], `\'`, "'"), nil
+	}
+	return strconv.Unquote(arg)
+}
+
+func escape(s string, opts map[InterpolateOption]struct{}) string {
+	if _, ok := opts[OptEscapeHtml]; ok {
+		s = html.EscapeString(s)
+	}
+	if _, ok := opts[OptEscapeUrl]; ok {
+		s = url.QueryEscape(s)
+	}
+	if _, ok := opts[OptEscapeJson]; ok {
+		var sb strings.Builder
+		enc := json.NewEncoder(&sb)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(s)
+		s = strings.TrimSuffix(sb.String(), "\n")
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package stringz
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Parallel()
+	data := map[string]any{
+		"order": map[string]any{
+			"id":     1001,
+			"status": "payment_pending",
+			"note":   nil,
+			"items": []any{
+				map[string]any{"sku": "A", "price": 1.5},
+				map[string]any{"sku": "B", "price": 2.25},
+			},
+			"tags": []string{"x", "y"},
+		},
+		"html": `<b>"hi"</b> & bye`,
+		"big":  1e6,
+	}
+	tests := []struct {
+		name    string
+		tmpl    string
+		opts    []InterpolateOption
+		want    string
+		wantErr error
+	}{
+		{name: "path", tmpl: "Order {{order.id}} total {{order.items.#.price|sum}}", want: "Order 1001 total 3.75"},
+		{name: "spaces and filters", tmpl: "{{ order.status | snake2title | upper }}", want: "PAYMENT PENDING"},
+		{name: "default for missing and null", tmpl: "{{order.nope|default:n/a}} {{order.note|default:'a|b}}'}}", want: "n/a a|b}}"},
+		{name: "default keep value", tmpl: "{{order.id|default:0}}", want: "1001"},
+		{name: "json", tmpl: `{{order.tags|json}} {{html|json}}`, want: `["x","y"] "\u003cb\u003e\"hi\"\u003c/b\u003e \u0026 bye"`},
+		{name: "map and slice as json", tmpl: "{{order.items.0}} {{order.items.#.sku}}", want: `{"price":1.5,"sku":"A"} ["A","B"]`},
+		{name: "join count first last", tmpl: `{{order.items.#.sku|join}} {{order.tags|join:"-"}} {{order.items|count}} {{html|count}} {{order.tags|first}}{{order.tags|last}}`, want: "A, B x-y 2 17 xy"},
+		{name: "float without exponent", tmpl: "{{big}}", want: "1000000"},
+		{name: "literal", tmpl: `\{{order.id}} {{order.id}} }} {`, want: "{{order.id}} 1001 }} {"},
+		{name: "escape html", tmpl: "<p>{{html}}</p>", opts: []InterpolateOption{OptEscapeHtml}, want: "<p>&lt;b&gt;&#34;hi&#34;&lt;/b&gt; &amp; bye</p>"},
+		{name: "escape url", tmpl: "?q={{html}}", opts: []InterpolateOption{OptEscapeUrl}, want: "?q=%3Cb%3E%22hi%22%3C%2Fb%3E+%26+bye"},
+		{name: "escape json", tmpl: `{"s":"{{html}}"}`, opts: []InterpolateOption{OptEscapeJson}, want: `{"s":"<b>\"hi\"</b> & bye"}`},
+		{name: "keep unresolved", tmpl: "{{order.id}} {{order.nope|upper}} {{order.note}}", opts: []InterpolateOption{OptKeepUnresolved}, want: "1001 {{order.nope|upper}} {{order.note}}"},
+		{name: "unresolved", tmpl: "{{order.nope}} {{order.note}}", wantErr: ErrUnresolved},
+		{name: "unknown filter", tmpl: "{{order.id|nope}}", wantErr: errors.New(`fail render {{order.id|nope}}: unknown filter "nope"`)},
+		{name: "sum of non number", tmpl: "{{order.tags|sum}}", wantErr: errors.New("fail render {{order.tags|sum}}: filter sum: sum of non number x")},
+		{name: "unclosed", tmpl: "a {{order.id", wantErr: errors.New(`unclosed placeholder at "{{order.id"`)},
+		{name: "empty path", tmpl: "{{|upper}}", wantErr: errors.New("fail render {{|upper}}: empty path")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Interpolate(tt.tmpl, data, tt.opts...)
+			if tt.wantErr != nil {
+				if err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error()) {
+					t.Errorf("Interpolate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Interpolate() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := Interpolate("{{a}} {{b.c}}", nil); err == nil || err.Error() != "unresolved placeholder: {{a}}, {{b.c}}" {
+		t.Errorf("Interpolate() error = %v", err)
+	}
+}
@@ -0,0 +1,518 @@
+package stringz
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+type JSONNonFinite string
+
+const (
+	NonFiniteError  JSONNonFinite = ""       //Fail as encoding/json
+	NonFiniteNull   JSONNonFinite = "null"   //NaN and ±Inf are null
+	NonFiniteString JSONNonFinite = "string" //"NaN", "Infinity" and "-Infinity" as JavaScript
+)
+
+type JSONBytes string
+
+const (
+	BytesBase64 JSONBytes = ""       //Base64 string as encoding/json
+	BytesString JSONBytes = "string" //String, invalid UTF-8 is replaced by U+FFFD
+	BytesArray  JSONBytes = "array"  //Array of numbers as conv.ToForce[string] of []byte
+)
+
+// JSONOptions configure Marshal, zero value is compact encoding/json without HTML escape.
+type JSONOptions struct {
+	Indent     string //Pretty print with indent e.g. "\t", "" is compact
+	EscapeHTML bool   //Escape <, > and & as <, > and &
+	// Canonical is RFC 8785 JSON Canonicalization Scheme for signature and hash (e.g. ToCrc32 of output): keys sorted by UTF-16 code units, numbers as ES6
+	// e.g. 1.0 is 1 and no HTML escape (EscapeHTML is ignored). Output is compact unless Indent is set. Numbers are float64 so integer beyond 2^53 is rounded, use string to keep it.
+	Canonical bool
+	NonFinite JSONNonFinite
+	Bytes     JSONBytes
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ToJsonE is ToJson with options and error.
+func ToJsonE(obj any, opts JSONOptions) (string, error) {
+	bs, err := opts.Marshal(obj)
+	return string(bs), err
+}
+
+// Marshal v as JSON by options, follow encoding/json rules e.g. json tags, json.Marshaler, encoding.TextMarshaler
+// and fields of embedded structs (shallowest or tagged field wins, ambiguous fields are dropped, declaration order).
+func (o JSONOptions) Marshal(v any) ([]byte, error) {
+	if o.NonFinite != NonFiniteError || o.Bytes != BytesBase64 {
+		var err error
+		if v, err = o.normalize(reflect.ValueOf(v), false); err != nil {
+			return nil, err
+		}
+	}
+	bs, err := encodeJson(v, o.EscapeHTML && !o.Canonical)
+	if err != nil {
+		return nil, err
+	}
+	if o.Canonical {
+		if bs, err = canonicalJson(bs); err != nil {
+			return nil, err
+		}
+	}
+	if o.Indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, bs, "", o.Indent); err != nil {
+			return nil, err
+		}
+		bs = buf.Bytes()
+	}
+	return bs, nil
+}
+
+func encodeJson(v any, escapeHTML bool) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(escapeHTML)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonObject keep order of struct fields after normalize
+type jsonObject struct {
+	keys []string
+	vals map[string]any
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		bs, err := encodeJson(k, false) //Outer encoder escape HTML if needed
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(bs)
+		buf.WriteByte(':')
+		if bs, err = encodeJson(o.vals[k], false); err != nil {
+			return nil, err
+		}
+		buf.Write(bs)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// normalize convert v to value that encoding/json can marshal with NonFinite and Bytes options.
+// addr is whether encoding/json see v as addressable, only then methods with pointer receiver are used even if v is an addressable copy.
+func (o JSONOptions) normalize(v reflect.Value, addr bool) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, nil
+	}
+	if addr && v.Kind() != reflect.Pointer && v.CanAddr() {
+		if pt := reflect.PointerTo(v.Type()); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+			v = v.Addr() //Method with pointer receiver
+		}
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		bs, err := v.Interface().(json.Marshaler).MarshalJSON()
+		return json.RawMessage(bs), err
+	}
+	if v.Type().Implements(textMarshalerType) {
+		bs, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(bs), err
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		return o.normalize(v.Elem(), true)
+	case reflect.Interface:
+		return o.normalize(v.Elem(), false)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return v.Interface(), nil
+		}
+		switch o.NonFinite {
+		case NonFiniteNull:
+			return nil, nil
+		case NonFiniteString:
+			return conv.Ternary(math.IsNaN(f), "NaN", conv.Ternary(f > 0, "Infinity", "-Infinity")), nil
+		}
+		return nil, fmt.Errorf("json: unsupported value: %v", f)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			switch o.Bytes {
+			case BytesString:
+				return string(v.Bytes()), nil
+			case BytesArray:
+				out := make([]uint16, v.Len()) //Change type because encoding/json marshal []uint8 as base64
+				for i, b := range v.Bytes() {
+					out[i] = uint16(b)
+				}
+				return out, nil
+			}
+			return v.Interface(), nil
+		}
+		return o.normalizeList(v, true)
+	case reflect.Array:
+		return o.normalizeList(v, addr)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := jsonMapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			if out[k], err = o.normalize(iter.Value(), false); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case reflect.Struct:
+		out := jsonObject{vals: map[string]any{}}
+		if err := o.normalizeStruct(v, addr, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	return v.Interface(), nil
+}
+
+func (o JSONOptions) normalizeList(v reflect.Value, addr bool) (any, error) {
+	out := make([]any, v.Len())
+	for i := range out {
+		var err error
+		if out[i], err = o.normalize(v.Index(i), addr); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func jsonMapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		bs, err := tm.MarshalText()
+		return string(bs), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("json: unsupported map key type %v", k.Type())
+}
+
+// jsonField is struct field to encode, resolved as encoding/json
+type jsonField struct {
+	name      string
+	tagged    bool
+	index     []int //Field index path through embedded structs
+	omitEmpty bool
+	quoted    bool //string option
+}
+
+var jsonFieldsCache sync.Map //reflect.Type to []jsonField
+
+// jsonFields return fields of struct t as encoding/json: fields of untagged embedded structs are promoted, for the same name
+// the shallowest wins then the tagged one, other conflicts are dropped. Fields are in declaration order with promoted fields at the embedded field position.
+func jsonFields(t reflect.Type) []jsonField {
+	if f, ok := jsonFieldsCache.Load(t); ok {
+		return f.([]jsonField)
+	}
+	type level struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []jsonField
+	current, next := []level{}, []level{{typ: t}}
+	var count, nextCount map[reflect.Type]int
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 { //Breadth-first by depth of embedding
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+		for _, l := range current {
+			if visited[l.typ] {
+				continue
+			}
+			visited[l.typ] = true
+			for i := 0; i < l.typ.NumField(); i++ {
+				sf := l.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, tagOpts, _ := strings.Cut(tag, ",")
+				index := append(append([]int{}, l.index...), i)
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, level{typ: ft, index: index})
+					}
+					continue
+				}
+				f := jsonField{name: name, tagged: name != "", index: index, omitEmpty: strings.Contains(","+tagOpts+",", ",omitempty,")}
+				if !f.tagged {
+					f.name = sf.Name
+				}
+				if strings.Contains(","+tagOpts+",", ",string,") {
+					switch ft.Kind() {
+					case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+						reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64, reflect.String:
+						f.quoted = true
+					}
+				}
+				fields = append(fields, f)
+				if count[l.typ] > 1 { //Embedded more than once at this depth, the duplicate make the name conflict and dropped
+					fields = append(fields, f)
+				}
+			}
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		return a.tagged && !b.tagged
+	})
+	out := make([]jsonField, 0, len(fields))
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		//Dominant is the first one unless the second is the same depth and taggedness
+		if j-i == 1 || len(fields[i].index) != len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			out = append(out, fields[i])
+		}
+		i = j
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].index, out[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	jsonFieldsCache.Store(t, out)
+	return out
+}
+
+// normalizeStruct add fields of jsonFields in order, see normalize for addr
+func (o JSONOptions) normalizeStruct(v reflect.Value, addr bool, out *jsonObject) error {
+	if !v.CanAddr() { //Copy to be addressable for unexported embedded struct, addr stay false
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+fields:
+	for _, f := range jsonFields(v.Type()) {
+		fv, fAddr := v, addr
+		for n, i := range f.index {
+			if n > 0 && fv.Kind() == reflect.Pointer { //Embedded pointer
+				if fv.IsNil() {
+					continue fields
+				}
+				fv, fAddr = fv.Elem(), true
+			}
+			fv = fv.Field(i)
+			if !fv.CanInterface() { //Exported fields of unexported embedded struct are promoted as encoding/json, drop read-only flag to read them
+				fv = reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem()
+			}
+		}
+		if f.omitEmpty && isEmptyJsonValue(fv) {
+			continue
+		}
+		val, err := o.normalize(fv, fAddr)
+		if err != nil {
+			return err
+		}
+		if f.quoted && val != nil {
+			bs, err := encodeJson(val, false)
+			if err != nil {
+				return err
+			}
+			val = string(bs)
+		}
+		out.keys = append(out.keys, f.name)
+		out.vals[f.name] = val
+	}
+	return nil
+}
+
+func isEmptyJsonValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// canonicalJson rewrite JSON as RFC 8785
+func canonicalJson(bs []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("fail decode for canonical json: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v any) error {
+	switch vv := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessUtf16(keys[i], keys[j]) })
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, vv[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range vv {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case string:
+		writeCanonicalString(buf, vv)
+	case json.Number:
+		s, err := canonicalNumber(vv)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	case bool:
+		buf.WriteString(strconv.FormatBool(vv))
+	case nil:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+// writeCanonicalString escape only quote, backslash and control characters as RFC 8785, U+2028 and U+2029 are literal unlike encoding/json
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUtf16 compare strings by UTF-16 code units as RFC 8785, it differ from UTF-8 bytes for surrogate pairs vs U+E000-U+FFFF
+func lessUtf16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// canonicalNumber format number as ECMAScript Number.prototype.toString of float64
+func canonicalNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) {
+		return "", fmt.Errorf("invalid number %v for canonical json", n)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+	s := strconv.FormatFloat(f, 'e', -1, 64) //e.g. 1e+21, 1.5e-07
+	mantissa, exp, _ := strings.Cut(s, "e")
+	return mantissa + "e" + exp[:1] + strings.TrimLeft(exp[1:], "0"), nil
+}
@@ -0,0 +1,220 @@
+package stringz
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+type jsonInner struct {
+	In  float64 `json:"in"`
+	Dup string
+}
+
+type jsonDoc struct {
+	jsonInner
+	Z     float64           `json:"z"`
+	A     []byte            `json:"a"`
+	Dup   string            `json:"dup,omitempty"`
+	Skip  string            `json:"-"`
+	Num   int               `json:"num,string"`
+	Empty []int             `json:"empty,omitempty"`
+	IP    net.IP            `json:"ip"`
+	T     time.Time         `json:"t"`
+	M     map[int]float64   `json:"m"`
+	Raw   json.RawMessage   `json:"raw"`
+	Ptr   *float64          `json:"ptr"`
+	Any   any               `json:"any"`
+	Arr   [2]float32        `json:"arr"`
+	Keys  map[string]string `json:"keys"`
+	inner string
+}
+
+func TestJSONOptions(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	doc := jsonDoc{
+		jsonInner: jsonInner{In: math.Inf(-1), Dup: "inner"},
+		Z:         math.Inf(1),
+		A:         []byte("<hi>"),
+		Num:       7,
+		IP:        net.IPv4(10, 0, 0, 1),
+		T:         time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		M:         map[int]float64{2: nan, 1: 1.5},
+		Raw:       json.RawMessage(`{"b":1, "a":2}`),
+		Ptr:       &nan,
+		Any:       []any{nan, "x"},
+		Arr:       [2]float32{1, float32(math.Inf(1))},
+		inner:     "x",
+	}
+	tests := []struct {
+		name    string
+		obj     any
+		opts    JSONOptions
+		want    string
+		wantErr bool
+	}{
+		{name: "default no html escape", obj: map[string]any{"a": "<&>", "b": []byte("hi")}, want: `{"a":"<&>","b":"aGk="}`},
+		{name: "escape html", obj: map[string]any{"a": "<&>"}, opts: JSONOptions{EscapeHTML: true}, want: `{"a":"\u003c\u0026\u003e"}`},
+		{name: "indent", obj: []int{1}, opts: JSONOptions{Indent: "  "}, want: "[\n  1\n]"},
+		{name: "nan error", obj: []float64{nan}, wantErr: true},
+		{name: "nan error with option", obj: []float64{nan}, opts: JSONOptions{Bytes: BytesString}, wantErr: true},
+		{name: "unsupported", obj: func() {}, wantErr: true},
+		{name: "unsupported map key", obj: map[float64]int{1: 1}, opts: JSONOptions{NonFinite: NonFiniteNull}, wantErr: true},
+		{
+			name: "struct null and string",
+			obj:  doc,
+			opts: JSONOptions{NonFinite: NonFiniteNull, Bytes: BytesString, EscapeHTML: true},
+			want: `{"in":null,"Dup":"inner","z":null,"a":"\u003chi\u003e","num":"7","ip":"10.0.0.1","t":"2024-01-02T03:04:05Z","m":{"1":1.5,"2":null},` +
+				`"raw":{"b":1,"a":2},"ptr":null,"any":[null,"x"],"arr":[1,null],"keys":null}`,
+		},
+		{
+			name: "non finite string, bytes array, canonical",
+			obj:  doc,
+			opts: JSONOptions{NonFinite: NonFiniteString, Bytes: BytesArray, Canonical: true, EscapeHTML: true},
+			want: `{"Dup":"inner","a":[60,104,105,62],"any":["NaN","x"],"arr":[1,"Infinity"],"in":"-Infinity","ip":"10.0.0.1","keys":null,` +
+				`"m":{"1":1.5,"2":"NaN"},"num":"7","ptr":"NaN","raw":{"a":2,"b":1},"t":"2024-01-02T03:04:05Z","z":"Infinity"}`,
+		},
+		{
+			name: "canonical numbers and strings",
+			obj:  json.RawMessage(`{"b":[1.0,1e2,-0.0,1.5e-7,1e21,123456789012345678901234],"a":"<é>","€":1,"\r":2}`),
+			opts: JSONOptions{Canonical: true},
+			want: `{"\r":2,"a":"<é>","b":[1,100,0,1.5e-7,1e+21,1.2345678901234569e+23],"€":1}`,
+		},
+		{
+			name: "canonical keys by utf-16",
+			obj:  map[string]int{"\ufb01": 1, "\U0001f600": 2, "\u00e9": 3},
+			opts: JSONOptions{Canonical: true},
+			want: "{\"\u00e9\":3,\"\U0001f600\":2,\"\ufb01\":1}",
+		},
+		{
+			name: "canonical line separators are literal",
+			obj:  "a\u2028b\u2029c\x01\"\\\t<",
+			opts: JSONOptions{Canonical: true},
+			want: "\"a\u2028b\u2029c\\u0001\\\"\\\\\\t<\"",
+		},
+		{name: "canonical pretty", obj: map[string]int{"b": 1, "a": 2}, opts: JSONOptions{Canonical: true, Indent: "\t"}, want: "{\n\t\"a\": 2,\n\t\"b\": 1\n}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToJsonE(tt.obj, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToJsonE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToJsonE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := ToJson(func() {}, ""); got != "" {
+		t.Errorf("ToJson() = %v, want empty on error", got)
+	}
+	canonical, _ := ToJsonE(map[string]any{"b": 2.0, "a": 1}, JSONOptions{Canonical: true})
+	if ToCrc32(canonical) != ToCrc32(`{"a":1,"b":2}`) {
+		t.Errorf("ToCrc32() of canonical json is not stable")
+	}
+}
+
+type jsonEmbedX1 struct {
+	X int
+	P int
+}
+
+type jsonEmbedX2 struct {
+	X int
+	Q int `json:"q,omitempty"`
+}
+
+type jsonEmbedTagged struct {
+	X int `json:"X"`
+}
+
+type jsonEmbedDeep struct {
+	jsonEmbedX1
+	D int
+}
+
+type jsonEmbedPtr struct {
+	R int
+}
+
+type jsonEmbedConflict struct {
+	Before int
+	jsonEmbedX1
+	jsonEmbedX2
+	Z int
+}
+
+type jsonEmbedDominant struct {
+	jsonEmbedX1
+	*jsonEmbedPtr
+	jsonEmbedTagged
+	Deep          jsonEmbedDeep
+	jsonEmbedDeep `json:"named"`
+	P             string
+}
+
+type jsonPtrMarshaler struct {
+	A int
+}
+
+func (*jsonPtrMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+type jsonPtrText struct {
+	B int
+}
+
+func (*jsonPtrText) MarshalText() ([]byte, error) {
+	return []byte("text"), nil
+}
+
+type jsonPtrHolder struct {
+	X jsonPtrMarshaler
+	T jsonPtrText
+	F float64
+	L []jsonPtrMarshaler
+	A [1]jsonPtrMarshaler
+	M map[string]jsonPtrMarshaler
+	I any
+	*jsonPtrEmbed
+}
+
+type jsonPtrEmbed struct {
+	E jsonPtrMarshaler
+}
+
+func TestJSONOptionsMatchEncodingJson(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		obj  any
+	}{
+		{name: "ambiguous fields are dropped", obj: jsonEmbedConflict{Before: 0, jsonEmbedX1: jsonEmbedX1{X: 1, P: 2}, jsonEmbedX2: jsonEmbedX2{X: 3, Q: 5}, Z: 4}},
+		{name: "dominant and tagged fields", obj: jsonEmbedDominant{jsonEmbedX1: jsonEmbedX1{X: 1, P: 2}, jsonEmbedPtr: &jsonEmbedPtr{R: 3}, jsonEmbedTagged: jsonEmbedTagged{X: 9}, P: "outer"}},
+		{name: "nil embedded pointer", obj: jsonEmbedDominant{}},
+		{name: "doc", obj: jsonDoc{jsonInner: jsonInner{In: 1, Dup: "inner"}, Dup: "outer", A: []byte("x"), Num: 3}},
+		{name: "pointer", obj: &jsonEmbedConflict{Z: 1}},
+		{name: "pointer receiver marshalers of value", obj: jsonPtrHolder{
+			F: 1, L: []jsonPtrMarshaler{{}}, M: map[string]jsonPtrMarshaler{"k": {}}, I: jsonPtrMarshaler{}, jsonPtrEmbed: &jsonPtrEmbed{},
+		}},
+		{name: "pointer receiver marshalers of pointer", obj: &jsonPtrHolder{F: 1, I: &jsonPtrText{}}},
+		{name: "pointer receiver marshalers in list", obj: []any{jsonPtrHolder{}, [1]jsonPtrHolder{}, map[string]jsonPtrHolder{"k": {}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := json.Marshal(tt.obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			//NonFinite use reflection encoder without changing finite values
+			got, err := ToJsonE(tt.obj, JSONOptions{NonFinite: NonFiniteNull, EscapeHTML: true})
+			if err != nil || got != string(want) {
+				t.Errorf("ToJsonE() = %v, %v, want %s", got, err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package stringz
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ReplaceAllStringSubmatchFunc replace all matches of re in str by repl of submatches, index 0 is whole match and i is group i.
+//
+// Groups keep their positions, unmatched optional group is "" (use ReplaceAllStringSubmatchIndexFunc to tell it from empty match).
+func ReplaceAllStringSubmatchFunc(re *regexp.Regexp, str string, repl func([]string) string) string {
+	n := re.NumSubexp() + 1
+	return ReplaceAllStringSubmatchIndexFunc(re, str, func(str string, v []int) string {
+		groups := make([]string, n) //New per match as repl may keep it
+		for i := range groups {
+			if v[2*i] >= 0 {
+				groups[i] = str[v[2*i]:v[2*i+1]]
+			} else {
+				groups[i] = ""
+			}
+		}
+		return repl(groups)
+	})
+}
+
+// ReplaceAllStringSubmatchNamedFunc is ReplaceAllStringSubmatchFunc with named groups e.g. (?P<year>\d{4}).
+//
+// Key "" is whole match, unmatched optional group is not in the map.
+func ReplaceAllStringSubmatchNamedFunc(re *regexp.Regexp, str string, repl func(map[string]string) string) string {
+	names := re.SubexpNames()
+	return ReplaceAllStringSubmatchIndexFunc(re, str, func(str string, v []int) string {
+		groups := make(map[string]string, len(names))
+		for i, name := range names {
+			if (i == 0 || name != "") && v[2*i] >= 0 {
+				groups[name] = str[v[2*i]:v[2*i+1]]
+			}
+		}
+		return repl(groups)
+	})
+}
+
+// ReplaceAllStringSubmatchIndexFunc replace all matches of re in str by repl of submatch index pairs as regexp.FindStringSubmatchIndex, -1 is unmatched group.
+func ReplaceAllStringSubmatchIndexFunc(re *regexp.Regexp, str string, repl func(str string, index []int) string) string {
+	indexes := re.FindAllStringSubmatchIndex(str, -1)
+	if len(indexes) == 0 {
+		return str
+	}
+	var sb strings.Builder
+	sb.Grow(len(str))
+	last := 0
+	for _, v := range indexes {
+		sb.WriteString(str[last:v[0]])
+		sb.WriteString(repl(str, v))
+		last = v[1]
+	}
+	sb.WriteString(str[last:])
+	return sb.String()
+}
+
+// ReplaceAllSubmatchFunc is ReplaceAllStringSubmatchFunc for byte slice, unmatched optional group is nil.
+//
+// Groups are sub-slices of b, so repl must not modify them. The result is always a new slice.
+func ReplaceAllSubmatchFunc(re *regexp.Regexp, b []byte, repl func([][]byte) []byte) []byte {
+	indexes := re.FindAllSubmatchIndex(b, -1)
+	out := make([]byte, 0, len(b))
+	last := 0
+	for _, v := range indexes {
+		groups := make([][]byte, re.NumSubexp()+1) //New per match as repl may keep it
+		for i := range groups {
+			if v[2*i] >= 0 {
+				groups[i] = b[v[2*i]:v[2*i+1]:v[2*i+1]]
+			} else {
+				groups[i] = nil
+			}
+		}
+		out = append(out, b[last:v[0]]...)
+		out = append(out, repl(groups)...)
+		last = v[1]
+	}
+	return append(out, b[last:]...)
+}
+
+// ReplaceAllStreamSubmatchFunc is ReplaceAllStringSubmatchFunc from r to w for large input, processed line by line.
+//
+// Match can't span lines and the line is without its line ending, so ^ and $ are the start and end of each line.
+func ReplaceAllStreamSubmatchFunc(re *regexp.Regexp, w io.Writer, r io.Reader, repl func([]string) string) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	bw := bufio.NewWriterSize(w, 64*1024)
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			content := strings.TrimSuffix(line, "\n")
+			eol := line[len(content):]
+			if strings.HasSuffix(content, "\r") {
+				content, eol = content[:len(content)-1], "\r"+eol
+			}
+			if _, werr := bw.WriteString(ReplaceAllStringSubmatchFunc(re, content, repl) + eol); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package stringz
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReplaceAllStringSubmatchFuncGroups(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile(`(a)?(b)(c)?`)
+	var got [][]string
+	out := ReplaceAllStringSubmatchFunc(re, "xbx abc", func(ss []string) string {
+		got = append(got, ss) //Keep without copy
+		return "[" + strings.Join(ss[1:], ",") + "]"
+	})
+	if want := [][]string{{"b", "", "b", ""}, {"abc", "a", "b", "c"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %q, want %q", got, want)
+	}
+	if want := "x[,b,]x [a,b,c]"; out != want {
+		t.Errorf("ReplaceAllStringSubmatchFunc() = %v, want %v", out, want)
+	}
+}
+
+func TestReplaceAllStringSubmatchNamedFunc(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile(`(?P<year>\d{4})-(?P<month>\d{2})(?:-(?P<day>\d{2}))?`)
+	got := ReplaceAllStringSubmatchNamedFunc(re, "from 2024-01-31 to 2024-02", func(m map[string]string) string {
+		day, ok := m["day"]
+		if !ok {
+			day = "??"
+		}
+		return fmt.Sprintf("%v/%v/%v(%v)", day, m["month"], m["year"], len(m[""]))
+	})
+	if want := "from 31/01/2024(10) to ??/02/2024(7)"; got != want {
+		t.Errorf("ReplaceAllStringSubmatchNamedFunc() = %v, want %v", got, want)
+	}
+}
+
+func TestReplaceAllStringSubmatchIndexFunc(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile(`(a)?b`)
+	got := ReplaceAllStringSubmatchIndexFunc(re, "ab b", func(s string, v []int) string {
+		return fmt.Sprint(v[2:])
+	})
+	if want := "[0 1] [-1 -1]"; got != want {
+		t.Errorf("ReplaceAllStringSubmatchIndexFunc() = %v, want %v", got, want)
+	}
+}
+
+func TestReplaceAllSubmatchFunc(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile(`(\w+)=(\d+)?`)
+	in := []byte("a=1 b= c=3")
+	got := ReplaceAllSubmatchFunc(re, in, func(bs [][]byte) []byte {
+		if bs[2] == nil {
+			return append(bs[1], ":nil"...) //Append must not overwrite the input
+		}
+		return append(append([]byte{}, bs[2]...), bs[1]...)
+	})
+	if want := "1a b:nil 3c"; string(got) != want {
+		t.Errorf("ReplaceAllSubmatchFunc() = %s, want %v", got, want)
+	}
+	if string(in) != "a=1 b= c=3" {
+		t.Errorf("ReplaceAllSubmatchFunc() modified input %s", in)
+	}
+	var kept [][]byte
+	ReplaceAllSubmatchFunc(re, in, func(bs [][]byte) []byte {
+		kept = append(kept, bs[1])
+		return nil
+	})
+	if want := [][]byte{[]byte("a"), []byte("b"), []byte("c")}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("ReplaceAllSubmatchFunc() kept groups = %q, want %q", kept, want)
+	}
+	none := []byte("none")
+	got = ReplaceAllSubmatchFunc(re, none, nil)
+	got[0] = 'N'
+	if string(got) != "None" || string(none) != "none" {
+		t.Errorf("ReplaceAllSubmatchFunc() no match = %s, input %s", got, none)
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write fail")
+}
+
+func TestReplaceAllStreamSubmatchFunc(t *testing.T) {
+	t.Parallel()
+	re := regexp.MustCompile(`^(\w+)=(.*)$`)
+	in := "a=1\r\nnot match\nb=2\n\nc=" + strings.Repeat("x", 100000)
+	var out bytes.Buffer
+	err := ReplaceAllStreamSubmatchFunc(re, &out, strings.NewReader(in), func(ss []string) string {
+		return ss[2] + "=" + ss[1]
+	})
+	if want := "1=a\r\nnot match\n2=b\n\n" + strings.Repeat("x", 100000) + "=c"; err != nil || out.String() != want {
+		t.Errorf("ReplaceAllStreamSubmatchFunc() = %.40q, %v", out.String(), err)
+	}
+	if err := ReplaceAllStreamSubmatchFunc(re, errWriter{}, strings.NewReader(in), func(ss []string) string { return "" }); err == nil {
+		t.Errorf("ReplaceAllStreamSubmatchFunc() expect write error")
+	}
+}
+
+func BenchmarkReplaceAllStringSubmatchFunc(b *testing.B) {
+	re := regexp.MustCompile(`(\w+)=(\d+)`)
+	s := strings.Repeat("key=123 ", 10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReplaceAllStringSubmatchFunc(re, s, func(ss []string) string {
+			return ss[2]
+		})
+	}
+}
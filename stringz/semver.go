@@ -0,0 +1,463 @@
+package stringz
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zev-zakaryan/go-util/conv"
+)
+
+var regVersionLenient = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?`)
+
+// Version is Semantic Versioning 2.0.0 https://semver.org, parse by ParseVersion or ParseVersionLenient.
+//
+// Zero value is 0.0.0. Build metadata is kept but ignored in comparison.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      []string
+}
+
+// ParseVersion parse strict SemVer 2.0.0 e.g. 1.2.3-rc.1+build.5, "v" prefix is not allowed.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	rest, build, hasBuild := strings.Cut(s, "+")
+	rest, pre, hasPre := strings.Cut(rest, "-")
+	nums := strings.Split(rest, ".")
+	if len(nums) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expect major.minor.patch", s)
+	}
+	for i, dst := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if !isNumericIdentifier(nums[i]) {
+			return Version{}, fmt.Errorf("invalid version %q: invalid number %q", s, nums[i])
+		}
+		n, err := strconv.ParseUint(nums[i], 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*dst = n
+	}
+	if hasPre {
+		v.Prerelease = strings.Split(pre, ".")
+		for _, id := range v.Prerelease {
+			if !isIdentifier(id) || (isDigits(id) && !isNumericIdentifier(id)) {
+				return Version{}, fmt.Errorf("invalid version %q: invalid pre-release identifier %q", s, id)
+			}
+		}
+	}
+	if hasBuild {
+		v.Build = strings.Split(build, ".")
+		for _, id := range v.Build {
+			if !isIdentifier(id) {
+				return Version{}, fmt.Errorf("invalid version %q: invalid build identifier %q", s, id)
+			}
+		}
+	}
+	return v, nil
+}
+
+// ParseVersionLenient parse the first version found anywhere in s, text around it is ignored e.g. "ver1.2" is 1.2.0 and "go1.21rc1" is 1.21.0.
+//
+// Missing minor and patch are 0 and leading zeros are allowed e.g. "v01.02" is 1.2.0. Pre-release after - and build after + end before
+// the first character not in [0-9A-Za-z.-] and empty identifiers are dropped e.g. "1.2.3-beta..1_x" is 1.2.3-beta.1, identifiers are not validated otherwise.
+// Error if there is no number or a number overflow uint64.
+func ParseVersionLenient(s string) (Version, error) {
+	ms := regVersionLenient.FindStringSubmatch(s)
+	if ms == nil {
+		return Version{}, fmt.Errorf("invalid version %q: no number", s)
+	}
+	var v Version
+	for i, dst := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if ms[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(ms[i+1], 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*dst = n
+	}
+	v.Prerelease = splitIdentifiers(ms[4])
+	v.Build = splitIdentifiers(ms[5])
+	return v, nil
+}
+
+// MustParseVersion is ParseVersion that panic on error, for constant version.
+func MustParseVersion(s string) Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func splitIdentifiers(s string) []string {
+	var out []string
+	for _, id := range strings.Split(s, ".") {
+		if id != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isNumericIdentifier is digits without leading zero
+func isNumericIdentifier(s string) bool {
+	return isDigits(s) && (s == "0" || s[0] != '0')
+}
+
+func isIdentifier(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (v Version) String() string {
+	out := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		out += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		out += "+" + strings.Join(v.Build, ".")
+	}
+	return out
+}
+
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parse by ParseVersion.
+func (v *Version) UnmarshalText(text []byte) error {
+	out, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = out
+	return nil
+}
+
+// Compare return -1, 0 or 1 by SemVer precedence e.g. 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta < 1.0.0. Build metadata is ignored.
+func (v Version) Compare(o Version) int {
+	if c := compareUint(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(o.Prerelease)))
+}
+
+func (v Version) Less(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compare numeric identifiers numerically (any length), numeric is lower than alphanumeric, otherwise ASCII order
+func compareIdentifier(a, b string) int {
+	numA, numB := isDigits(a), isDigits(b)
+	switch {
+	case numA && numB:
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+	case numA:
+		return -1
+	case numB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// SortVersions sort vs ascending by precedence, equal versions keep their order.
+func SortVersions(vs []Version) {
+	sort.SliceStable(vs, func(i, j int) bool {
+		return vs[i].Less(vs[j])
+	})
+}
+
+// BumpMajor e.g. 1.2.3 is 2.0.0, pre-release of major e.g. 2.0.0-rc.1 is released as 2.0.0. Build metadata is removed.
+func (v Version) BumpMajor() Version {
+	if len(v.Prerelease) > 0 && v.Minor == 0 && v.Patch == 0 {
+		return Version{Major: v.Major}
+	}
+	return Version{Major: v.Major + 1}
+}
+
+// BumpMinor e.g. 1.2.3 is 1.3.0, pre-release of minor e.g. 1.3.0-rc.1 is released as 1.3.0. Build metadata is removed.
+func (v Version) BumpMinor() Version {
+	if len(v.Prerelease) > 0 && v.Patch == 0 {
+		return Version{Major: v.Major, Minor: v.Minor}
+	}
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// BumpPatch e.g. 1.2.3 is 1.2.4, pre-release e.g. 1.2.4-rc.1 is released as 1.2.4. Build metadata is removed.
+func (v Version) BumpPatch() Version {
+	if len(v.Prerelease) > 0 {
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+type comparator struct {
+	op string
+	v  Version
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	}
+	return cmp <= 0
+}
+
+// Constraint is version range, parse by ParseConstraint.
+type Constraint struct {
+	raw  string
+	sets [][]comparator //OR of AND
+}
+
+// ParseConstraint parse range as npm e.g. ">=1.2 <2.0 || ^3.1".
+//
+// Comparators separated by space or comma must all match, || separate alternatives. Operators are =, !=, >, >=, <, <=,
+// ~ (patch updates e.g. ~1.2.3 is >=1.2.3 <1.3.0) and ^ (updates that keep the left-most non-zero number e.g. ^0.2.3 is >=0.2.3 <0.3.0).
+// Version can be partial with x or * e.g. 1.2, 1.x and * for any. Hyphen range e.g. "1.2 - 2.3" is >=1.2.0 <2.4.0.
+//
+// Pre-release version only match if a comparator of the same set has pre-release of the same major.minor.patch
+// e.g. >=1.2.3-beta match 1.2.3-rc but not 1.2.4-rc.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+	for _, alt := range strings.Split(s, "||") {
+		tokens := strings.Fields(strings.ReplaceAll(alt, ",", " "))
+		var set []comparator
+		for i := 0; i < len(tokens); i++ {
+			tok := tokens[i]
+			if i+2 < len(tokens) && tokens[i+1] == "-" {
+				cs, err := parseHyphenRange(tok, tokens[i+2])
+				if err != nil {
+					return Constraint{}, fmt.Errorf("invalid constraint %q: %w", s, err)
+				}
+				set = append(set, cs...)
+				i += 2
+				continue
+			}
+			if strings.Trim(tok, "=!<>~^") == "" && i+1 < len(tokens) { //Operator separated by space e.g. ">= 1.2"
+				i++
+				tok += tokens[i]
+			}
+			cs, err := parseComparator(tok)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			set = append(set, cs...)
+		}
+		if len(set) == 0 {
+			set = []comparator{{op: ">=", v: Version{}}}
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+// MustParseConstraint is ParseConstraint that panic on error, for constant constraint.
+func MustParseConstraint(s string) Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check return true if v is in range.
+func (c Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if checkSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkSet(set []comparator, v Version) bool {
+	for _, cmp := range set {
+		if !cmp.check(v) {
+			return false
+		}
+	}
+	if len(v.Prerelease) == 0 {
+		return true
+	}
+	for _, cmp := range set {
+		if len(cmp.v.Prerelease) > 0 && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePartial parse version that may miss minor and patch or use x/X/* as wildcard, n is count of specified numbers
+func parsePartial(s string) (v Version, n int, err error) {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return Version{}, 0, nil
+	}
+	nums := strings.SplitN(s, ".", 3)
+	for i, dst := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if i >= len(nums) || nums[i] == "x" || nums[i] == "X" || nums[i] == "*" {
+			return v, n, nil
+		}
+		if i < 2 {
+			if *dst, err = strconv.ParseUint(nums[i], 10, 64); err != nil {
+				return Version{}, 0, fmt.Errorf("invalid version %q", s)
+			}
+			n++
+			continue
+		}
+		full, err := ParseVersion(strings.Join(nums, "."))
+		if err != nil {
+			return Version{}, 0, err
+		}
+		return full, 3, nil
+	}
+	return v, n, nil
+}
+
+func parseComparator(s string) ([]comparator, error) {
+	op := s[:len(s)-len(strings.TrimLeft(s, "=!<>~^"))]
+	v, n, err := parsePartial(s[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	lower := comparator{op: ">=", v: v}
+	switch op {
+	case "", "=", "==":
+		if n == 3 {
+			return []comparator{{op: "=", v: v}}, nil
+		}
+		return rangeOf(lower, upperOf(v, n)), nil
+	case "!=":
+		return []comparator{{op: "!=", v: v}}, nil
+	case ">":
+		if n == 3 {
+			return []comparator{{op: ">", v: v}}, nil
+		}
+		if n == 0 {
+			return []comparator{{op: "<", v: Version{}}}, nil //Nothing
+		}
+		return []comparator{{op: ">=", v: upperOf(v, n).v}}, nil
+	case ">=":
+		return []comparator{lower}, nil
+	case "<":
+		return []comparator{{op: "<", v: v}}, nil
+	case "<=":
+		if n == 3 {
+			return []comparator{{op: "<=", v: v}}, nil
+		}
+		return rangeOf(comparator{op: ">=", v: Version{}}, upperOf(v, n)), nil
+	case "~":
+		return rangeOf(lower, upperOf(v, conv.Ternary(n > 2, 2, n))), nil
+	case "^":
+		switch {
+		case n == 0:
+		case v.Major > 0 || n == 1:
+			n = 1
+		case v.Minor > 0 || n == 2:
+			n = 2
+		}
+		return rangeOf(lower, upperOf(v, n)), nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func parseHyphenRange(from, to string) ([]comparator, error) {
+	vFrom, _, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	vTo, n, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+	if n == 3 {
+		return []comparator{{op: ">=", v: vFrom}, {op: "<=", v: vTo}}, nil
+	}
+	return rangeOf(comparator{op: ">=", v: vFrom}, upperOf(vTo, n)), nil
+}
+
+// upperOf return exclusive upper bound when only first n numbers of v are fixed, n=0 is no bound
+func upperOf(v Version, n int) comparator {
+	switch n {
+	case 1:
+		return comparator{op: "<", v: Version{Major: v.Major + 1}}
+	case 2:
+		return comparator{op: "<", v: Version{Major: v.Major, Minor: v.Minor + 1}}
+	case 3:
+		return comparator{op: "<", v: Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}}
+	}
+	return comparator{}
+}
+
+func rangeOf(lower, upper comparator) []comparator {
+	if upper.op == "" {
+		return []comparator{lower}
+	}
+	return []comparator{lower, upper}
+}
@@ -0,0 +1,184 @@
+package stringz
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s       string
+		want    Version
+		wantErr bool
+	}{
+		{s: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{s: "0.0.0-alpha.1+build.001.x-y", want: Version{Prerelease: []string{"alpha", "1"}, Build: []string{"build", "001", "x-y"}}},
+		{s: "1.0.0-x-y.0a", want: Version{Major: 1, Prerelease: []string{"x-y", "0a"}}},
+		{s: "v1.2.3", wantErr: true},
+		{s: "1.2", wantErr: true},
+		{s: "1.2.3.4", wantErr: true},
+		{s: "01.2.3", wantErr: true},
+		{s: "1.2.3-01", wantErr: true},
+		{s: "1.2.3-", wantErr: true},
+		{s: "1.2.3-a..b", wantErr: true},
+		{s: "1.2.3+a_b", wantErr: true},
+		{s: "1.2.99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (!reflect.DeepEqual(got, tt.want) || got.String() != tt.s) {
+			t.Errorf("ParseVersion(%q) = %#v, want %#v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionLenient(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{s: "1.2.3", want: "1.2.3"},
+		{s: "ver11.22", want: "11.22.0"},
+		{s: "ver#1111.2222.3333alpha", want: "1111.2222.3333"},
+		{s: "v1.02.3-beta..1+x", want: "1.2.3-beta.1+x"},
+		{s: "go1.21", want: "1.21.0"},
+		{s: "go1.21rc1", want: "1.21.0"},
+		{s: "release 1.2.3 final", want: "1.2.3"},
+		{s: "7", want: "7.0.0"},
+		{s: "v01.02", want: "1.2.0"},
+		{s: "v01.02.003", want: "1.2.3"},
+		{s: "1.2.3-beta..1_x", want: "1.2.3-beta.1"},
+		{s: "1.2.3-rc.+b..1", want: "1.2.3-rc+b.1"},
+		{s: "1.2.3+exp.sha_5", want: "1.2.3+exp.sha"},
+		{s: "1.2.3-01", want: "1.2.3-01"},
+		{s: "latest", wantErr: true},
+		{s: "99999999999999999999.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersionLenient(tt.s)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got.String() != tt.want) {
+			t.Errorf("ParseVersionLenient(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	t.Parallel()
+	sorted := []string{
+		"0.9.9", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11",
+		"1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "1.10.0", "2.0.0-99999999999999999999", "2.0.0-100000000000000000000", "2.0.0",
+	}
+	vs := make([]Version, len(sorted))
+	for i, s := range sorted {
+		vs[i] = MustParseVersion(s)
+	}
+	for i := range vs {
+		for j := range vs {
+			want := compareUint(uint64(i), uint64(j))
+			if got := vs[i].Compare(vs[j]); got != want {
+				t.Errorf("%v.Compare(%v) = %v, want %v", vs[i], vs[j], got, want)
+			}
+		}
+	}
+	if MustParseVersion("1.0.0+a").Compare(MustParseVersion("1.0.0+b")) != 0 {
+		t.Errorf("Compare() must ignore build metadata")
+	}
+	shuffled := append([]Version{}, vs...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	SortVersions(shuffled)
+	if !reflect.DeepEqual(shuffled, vs) {
+		t.Errorf("SortVersions() = %v", shuffled)
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s                   string
+		major, minor, patch string
+	}{
+		{s: "1.2.3+build", major: "2.0.0", minor: "1.3.0", patch: "1.2.4"},
+		{s: "2.0.0-rc.1", major: "2.0.0", minor: "2.0.0", patch: "2.0.0"},
+		{s: "1.3.0-rc.1", major: "2.0.0", minor: "1.3.0", patch: "1.3.0"},
+		{s: "1.2.4-rc.1", major: "2.0.0", minor: "1.3.0", patch: "1.2.4"},
+	}
+	for _, tt := range tests {
+		v := MustParseVersion(tt.s)
+		if got := [3]string{v.BumpMajor().String(), v.BumpMinor().String(), v.BumpPatch().String()}; got != [3]string{tt.major, tt.minor, tt.patch} {
+			t.Errorf("Bump %v = %v", tt.s, got)
+		}
+	}
+}
+
+func TestVersionJson(t *testing.T) {
+	t.Parallel()
+	var got struct{ V Version }
+	if err := json.Unmarshal([]byte(`{"V":"1.2.3-rc.1"}`), &got); err != nil || got.V.String() != "1.2.3-rc.1" {
+		t.Errorf("Unmarshal() = %v, %v", got.V, err)
+	}
+	if bs, _ := json.Marshal(got); string(bs) != `{"V":"1.2.3-rc.1"}` {
+		t.Errorf("Marshal() = %s", bs)
+	}
+	if err := json.Unmarshal([]byte(`{"V":"1.2"}`), &got); err == nil {
+		t.Errorf("Unmarshal() expect error")
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		c     string
+		match []string
+		fail  []string
+	}{
+		{c: ">=1.2 <2.0 || ^3.1", match: []string{"1.2.0", "1.9.9", "3.1.0", "3.9.0"}, fail: []string{"1.1.9", "2.0.0", "3.0.9", "4.0.0", "1.5.0-rc.1"}},
+		{c: "^1.2.3", match: []string{"1.2.3", "1.9.0"}, fail: []string{"1.2.2", "2.0.0", "2.0.0-alpha"}},
+		{c: "^0.2.3", match: []string{"0.2.3", "0.2.9"}, fail: []string{"0.3.0"}},
+		{c: "^0.0.3", match: []string{"0.0.3"}, fail: []string{"0.0.4"}},
+		{c: "^0.0", match: []string{"0.0.9"}, fail: []string{"0.1.0"}},
+		{c: "~1.2.3", match: []string{"1.2.3", "1.2.9"}, fail: []string{"1.3.0"}},
+		{c: "~1", match: []string{"1.0.0", "1.9.0"}, fail: []string{"2.0.0"}},
+		{c: "1.2.x", match: []string{"1.2.0", "1.2.9"}, fail: []string{"1.3.0", "1.1.9"}},
+		{c: "1", match: []string{"1.0.0", "1.9.9"}, fail: []string{"2.0.0"}},
+		{c: "*", match: []string{"0.0.0", "9.9.9"}, fail: []string{"1.0.0-rc.1"}},
+		{c: "", match: []string{"1.0.0"}},
+		{c: ">1.2", match: []string{"1.3.0"}, fail: []string{"1.2.9"}},
+		{c: "<=1.2", match: []string{"1.2.9", "0.1.0"}, fail: []string{"1.3.0"}},
+		{c: ">= 1.0.0, != 1.5.0", match: []string{"1.0.0", "1.6.0"}, fail: []string{"1.5.0"}},
+		{c: "=v1.2.3", match: []string{"1.2.3", "1.2.3+build"}, fail: []string{"1.2.4"}},
+		{c: "1.2 - 2.3", match: []string{"1.2.0", "2.3.9"}, fail: []string{"2.4.0", "1.1.0"}},
+		{c: "1.2.3 - 2.3.4", match: []string{"2.3.4"}, fail: []string{"2.3.5"}},
+		{c: ">=1.2.3-beta <1.3", match: []string{"1.2.3-beta", "1.2.3-rc.1", "1.2.3"}, fail: []string{"1.2.3-alpha", "1.2.4-rc.1"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.c)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) error = %v", tt.c, err)
+			continue
+		}
+		for _, s := range tt.match {
+			if !c.Check(MustParseVersion(s)) {
+				t.Errorf("%q.Check(%v) = false, want true", tt.c, s)
+			}
+		}
+		for _, s := range tt.fail {
+			if c.Check(MustParseVersion(s)) {
+				t.Errorf("%q.Check(%v) = true, want false", tt.c, s)
+			}
+		}
+	}
+	for _, s := range []string{"~>1.2", "1.a", ">=1.2.3-01", "1.2 - 2.a"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) expect error", s)
+		}
+	}
+}
@@ -1,7 +1,7 @@
+// Package stringz is string helpers: case, index, interpolation, json, semver and checksums. It builds on conv.
 package stringz
 
 import (
-	"encoding/json"
 	"fmt"
 	"hash/crc32"
 	"regexp"
@@ -9,6 +9,9 @@ import (
 	"strings"
 )
 
+// GetVersionAsInteger pack the first major.minor.patch in version to decimal string, minor and patch are clamped at 999.
+//
+// Use ParseVersionLenient and Version.Compare for correct comparison including pre-release.
 func GetVersionAsInteger(version string) string {
 	out := ""
 	r := regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
@@ -35,13 +38,26 @@ func GetVersionAsInteger(version string) string {
 //	@s haystack
 //	@substr needle
 //	@nth nth needle from 1
-func IndexOfNth(s string, substr string, nth int) int {
+//	@opts OptOverlap, OptIgnoreCase, OptRuneIndex or OptGraphemeIndex, see AllIndexes
+//
+// Return byte index by default, -1 if not found and 0 if substr is empty.
+func IndexOfNth(s string, substr string, nth int, opts ...IndexOption) int {
 	if substr == "" {
 		return 0
 	}
 	if nth < 1 {
 		nth = 1
 	}
+	if len(opts) > 0 {
+		out := -1
+		AllIndexes(s, substr, opts...)(func(i int) bool {
+			if nth--; nth == 0 {
+				out = i
+			}
+			return nth > 0
+		})
+		return out
+	}
 	l := len(substr)
 	out := -l
 	for i := 0; i < nth; i++ {
@@ -82,45 +98,29 @@ func IndexOfNth(s string, substr string, nth int) int {
 // 	}
 // }
 
-func ReplaceAllStringSubmatchFunc(re *regexp.Regexp, str string, repl func([]string) string) string {
-	result := ""
-	lastIndex := 0
-
-	for _, v := range re.FindAllSubmatchIndex([]byte(str), -1) {
-		groups := make([]string, 0)
-		for i := 0; i < len(v); i += 2 {
-			if v[i] >= 0 && v[i+1] >= 0 { //Note: should always but to be safe
-				groups = append(groups, str[v[i]:v[i+1]])
-			}
-		}
-
-		result += str[lastIndex:v[0]] + repl(groups)
-		lastIndex = v[1]
-	}
-
-	return result + str[lastIndex:]
-}
+var (
+	regUnderscores = regexp.MustCompile(`_+`)
+	regWordStart   = regexp.MustCompile(`(^|\s)\w`)
+)
 
+// Snake2Title replace underscores with space and uppercase first letter of each word, other characters are kept as is.
+//
+// See ToTitle for full case conversion e.g. from camelCase.
 func Snake2Title(s string) string {
-	mUs := regexp.MustCompile(`_+`)
-	mC1 := regexp.MustCompile(`(^|\s)\w`)
-
-	return mC1.ReplaceAllStringFunc(mUs.ReplaceAllLiteralString(strings.Trim(s, "_"), " "), func(w string) string {
+	return regWordStart.ReplaceAllStringFunc(regUnderscores.ReplaceAllLiteralString(strings.Trim(s, "_"), " "), func(w string) string {
 		return strings.ToUpper(w)
 	})
 }
 
-// ToCrc32 return Crc32 zero padding to 8 digits
+// ToCrc32 return Crc32 zero padding to 8 digits of fmt %v of v.
+//
+// Use hashz.Value or ToCrc32 of ToJsonE with JSONOptions.Canonical for stable hash of map or struct, and hashz for other algorithms.
 func ToCrc32(v interface{}) string {
 	return strings.ToUpper(fmt.Sprintf("%08s", strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(fmt.Sprintf("%v", v)))), 16)))
 }
 
+// ToJson marshal obj with HTML escape, indent "" is compact. Return "" on error, use ToJsonE for error and other options.
 func ToJson(obj interface{}, indent string) string {
-	var bs []byte
-	if indent != "" {
-		bs, _ = json.MarshalIndent(obj, "", indent)
-	} else {
-		bs, _ = json.Marshal(obj)
-	}
-	return string(bs)
+	out, _ := ToJsonE(obj, JSONOptions{Indent: indent, EscapeHTML: true})
+	return out
 }